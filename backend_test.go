@@ -0,0 +1,212 @@
+package tokenup_sdk_test
+
+import (
+	"crypto/ecdsa"
+	"encoding/json"
+	"fmt"
+	"github.com/cblk/tokenup-sdk"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// nodeHandler 处理节点网关的一个路径，返回状态码与 data，状态码不为200时 data 为错误信息
+type nodeHandler func(path string, body []byte) (int, interface{})
+
+// fakeNode 模拟节点网关与签名服务，记录节点网关每个路径收到的请求体
+// 节点网关的路径由测试通过 handle/reply 配置，未配置的路径返回404；以 "/" 结尾的路径匹配该前缀下的所有路径
+// 签名服务(/vendor/)使用 key 对交易哈希做 secp256k1 签名，返回 0x 开头的 65 字节 R||S||V
+type fakeNode struct {
+	key *ecdsa.PrivateKey
+
+	mu         sync.Mutex
+	routes     map[string]nodeHandler
+	bodies     map[string][][]byte
+	signatures map[string]string
+}
+
+func newFakeNode(t *testing.T) (*fakeNode, *tokenup_sdk.Client) {
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	n := &fakeNode{
+		key:        key,
+		routes:     map[string]nodeHandler{},
+		bodies:     map[string][][]byte{},
+		signatures: map[string]string{},
+	}
+	srv := httptest.NewServer(n)
+	t.Cleanup(srv.Close)
+	priv, pub := newTestKeys(t)
+	client, err := tokenup_sdk.NewClient(tokenup_sdk.NodeConfig{NodeUrl: srv.URL}, tokenup_sdk.Authorize{
+		SignerUrl:              srv.URL,
+		AppId:                  "app",
+		AppKey:                 "key",
+		PrivateKey:             priv,
+		CallBackPartyPublicKey: pub,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return n, client
+}
+
+// address 签名服务托管的地址
+func (n *fakeNode) address() string {
+	return crypto.PubkeyToAddress(n.key.PublicKey).Hex()
+}
+
+func (n *fakeNode) handle(path string, h nodeHandler) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.routes[path] = h
+}
+
+// reply path 固定返回 data
+func (n *fakeNode) reply(path string, data interface{}) {
+	n.handle(path, func(string, []byte) (int, interface{}) { return http.StatusOK, data })
+}
+
+// acceptTx tx/estimate 返回 estimateData()，tx/transact 接受所有交易并返回 txHash
+func (n *fakeNode) acceptTx() {
+	n.reply("/v1/tx/estimate", estimateData())
+	n.handle("/v1/tx/transact", func(_ string, body []byte) (int, interface{}) {
+		var req tokenup_sdk.TransactRequest
+		_ = json.Unmarshal(body, &req)
+		return http.StatusOK, map[string]interface{}{"tx_hash": txHash(req), "gas_price": req.GasPrice, "gas_limit": req.GasLimit, "status": 1}
+	})
+}
+
+// replyCalls tx/call 按 data 的4字节方法选择器返回 results 中的结果
+func (n *fakeNode) replyCalls(results map[string]string) {
+	n.handle("/v1/tx/call", func(_ string, body []byte) (int, interface{}) {
+		var req tokenup_sdk.CallRequest
+		_ = json.Unmarshal(body, &req)
+		if len(req.Data) < 10 {
+			return http.StatusBadRequest, "execution reverted"
+		}
+		result, ok := results[req.Data[:10]]
+		if !ok {
+			return http.StatusBadRequest, "execution reverted"
+		}
+		return http.StatusOK, result
+	})
+}
+
+// count path 收到的请求数
+func (n *fakeNode) count(path string) int {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	return len(n.bodies[path])
+}
+
+// decode 把 path 收到的所有请求体按顺序解码到 v，v 为切片指针
+func (n *fakeNode) decode(path string, v interface{}) {
+	n.mu.Lock()
+	bodies := make([]string, len(n.bodies[path]))
+	for i, b := range n.bodies[path] {
+		bodies[i] = string(b)
+	}
+	n.mu.Unlock()
+	_ = json.Unmarshal([]byte("["+strings.Join(bodies, ",")+"]"), v)
+}
+
+// sent tx/transact 收到的交易
+func (n *fakeNode) sent() []tokenup_sdk.TransactRequest {
+	var sent []tokenup_sdk.TransactRequest
+	n.decode("/v1/tx/transact", &sent)
+	return sent
+}
+
+// signed 签名服务返回过的签名
+func (n *fakeNode) signed() []string {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	var sigs []string
+	for _, sig := range n.signatures {
+		sigs = append(sigs, sig)
+	}
+	return sigs
+}
+
+func (n *fakeNode) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, _ := ioutil.ReadAll(r.Body)
+	w.Header().Set("Content-Type", "application/json")
+	if strings.HasPrefix(r.URL.Path, "/vendor/") {
+		n.serveSigner(w, r.URL.Path, body)
+		return
+	}
+	n.mu.Lock()
+	n.bodies[r.URL.Path] = append(n.bodies[r.URL.Path], body)
+	h := n.route(r.URL.Path)
+	n.mu.Unlock()
+	code, data := http.StatusNotFound, interface{}("not found")
+	if h != nil {
+		// handler 不持有锁，可以阻塞或回调 fakeNode 的方法
+		code, data = h(r.URL.Path, body)
+	}
+	w.WriteHeader(code)
+	if code != http.StatusOK {
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"message": fmt.Sprint(data)})
+		return
+	}
+	_ = json.NewEncoder(w).Encode(map[string]interface{}{"message": "success", "data": data})
+}
+
+func (n *fakeNode) route(path string) nodeHandler {
+	if h, ok := n.routes[path]; ok {
+		return h
+	}
+	var (
+		prefix string
+		h      nodeHandler
+	)
+	for p, ph := range n.routes {
+		if strings.HasSuffix(p, "/") && strings.HasPrefix(path, p) && len(p) > len(prefix) {
+			prefix, h = p, ph
+		}
+	}
+	return h
+}
+
+func (n *fakeNode) serveSigner(w http.ResponseWriter, path string, body []byte) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	switch path {
+	case "/vendor/proxy/sign_hash":
+		var req tokenup_sdk.ProxySignSafe
+		_ = json.Unmarshal(body, &req)
+		sig, err := crypto.Sign(hexutil.MustDecode(req.Data), n.key)
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{"status": map[string]interface{}{"message": err.Error()}})
+			return
+		}
+		n.signatures[req.OrderID] = hexutil.Encode(sig)
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"status": map[string]interface{}{"code": 200}, "data": map[string]interface{}{"request_id": req.OrderID}})
+	case "/vendor/status/tracing":
+		var req tokenup_sdk.TraceSafe
+		_ = json.Unmarshal(body, &req)
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"status": map[string]interface{}{"code": 200}, "data": map[string]interface{}{
+			"result": map[string]interface{}{"data": n.signatures[req.RequestId]},
+		}})
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+// estimateData tx/estimate 的返回值：gas price 10 Gwei、gas 21000、nonce 7、chain id 3
+func estimateData() map[string]interface{} {
+	return map[string]interface{}{"gas_price": "0x2540be400", "gas": "0x5208", "nonce": 7, "chain_id": 3}
+}
+
+// txHash acceptTx 为交易生成的哈希，from、nonce 与 gas price 相同的交易哈希相同
+func txHash(req tokenup_sdk.TransactRequest) string {
+	return crypto.Keccak256Hash([]byte(fmt.Sprintf("%s-%d-%s", req.From, req.Nonce, req.GasPrice))).Hex()
+}
@@ -0,0 +1,236 @@
+// Package bind 根据合约 ABI 生成基于 tokenup_sdk.Client 的 Go 绑定代码
+package bind
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"go/format"
+	"go/token"
+	"sort"
+	"strings"
+	"text/template"
+	"unicode"
+)
+
+// Config 生成代码的配置
+type Config struct {
+	Package string // 生成代码的包名
+	Type    string // 合约类型名，如 Token
+	ABI     string // 合约 ABI JSON
+}
+
+type tmplData struct {
+	Package string
+	Type    string
+	ABI     string
+	Calls   []tmplMethod
+	Sends   []tmplMethod
+	Events  []tmplEvent
+}
+
+type tmplArg struct {
+	Name string // 生成代码中的参数名
+	Key  string // ABI 中的参数名
+	Type string
+}
+
+type tmplMethod struct {
+	Name    string // Go 方法名
+	AbiName string // abi.ABI.Methods 中的名称
+	Sig     string
+	Payable bool
+	Inputs  []tmplArg
+	Outputs []tmplArg
+}
+
+type tmplEvent struct {
+	Name    string
+	AbiName string
+	Sig     string
+	Fields  []tmplArg
+	Indexed []tmplArg // Filter 方法的参数，Type 为元素类型
+}
+
+// 生成代码中使用的局部变量，ABI 参数同名时加下划线
+var reservedNames = map[string]bool{
+	"c": true, "txFrom": true, "txValue": true, "values": true, "err": true,
+	"fromBlock": true, "toBlock": true, "topics": true, "res": true, "query": true,
+}
+
+// Generate 生成格式化后的 Go 源码
+func Generate(cfg Config) ([]byte, error) {
+	if cfg.Package == "" || cfg.Type == "" {
+		return nil, errors.New("bind: package and type are required")
+	}
+	if !token.IsIdentifier(cfg.Package) || !token.IsIdentifier(cfg.Type) {
+		return nil, fmt.Errorf("bind: invalid package %q or type %q", cfg.Package, cfg.Type)
+	}
+	contractABI, err := abi.JSON(strings.NewReader(cfg.ABI))
+	if err != nil {
+		return nil, fmt.Errorf("bind: %v", err)
+	}
+	var compact bytes.Buffer
+	if err := json.Compact(&compact, []byte(cfg.ABI)); err != nil {
+		return nil, fmt.Errorf("bind: %v", err)
+	}
+	data := tmplData{
+		Package: cfg.Package,
+		Type:    cfg.Type,
+		ABI:     fmt.Sprintf("%q", compact.String()),
+	}
+	for _, name := range sortedKeys(len(contractABI.Methods), func(add func(string)) {
+		for k := range contractABI.Methods {
+			add(k)
+		}
+	}) {
+		m := contractABI.Methods[name]
+		tm := tmplMethod{
+			Name:    abi.ToCamelCase(m.Name),
+			AbiName: m.Name,
+			Sig:     m.Sig,
+			Payable: m.IsPayable(),
+			Inputs:  args(m.Inputs, "arg"),
+		}
+		if m.IsConstant() {
+			tm.Outputs = make([]tmplArg, len(m.Outputs))
+			for i, out := range m.Outputs {
+				tm.Outputs[i] = tmplArg{Name: fmt.Sprintf("out%d", i), Key: out.Name, Type: goType(out.Type)}
+			}
+			data.Calls = append(data.Calls, tm)
+		} else {
+			data.Sends = append(data.Sends, tm)
+		}
+	}
+	for _, name := range sortedKeys(len(contractABI.Events), func(add func(string)) {
+		for k := range contractABI.Events {
+			add(k)
+		}
+	}) {
+		e := contractABI.Events[name]
+		if e.Anonymous {
+			continue
+		}
+		te := tmplEvent{Name: abi.ToCamelCase(e.Name), AbiName: e.Name, Sig: e.Sig}
+		for i, arg := range e.Inputs {
+			field := tmplArg{Name: abi.ToCamelCase(argName(arg.Name, "arg", i)), Key: arg.Name, Type: goType(arg.Type)}
+			if arg.Indexed {
+				if isHashedTopic(arg.Type) {
+					field.Type = "common.Hash"
+				}
+				rule := tmplArg{Name: paramName(arg.Name, "arg", i), Type: field.Type}
+				if arg.Type.T == abi.StringTy || arg.Type.T == abi.BytesTy {
+					// MakeTopics 对 string/bytes 计算哈希
+					rule.Type = goType(arg.Type)
+				}
+				te.Indexed = append(te.Indexed, rule)
+			}
+			te.Fields = append(te.Fields, field)
+		}
+		data.Events = append(data.Events, te)
+	}
+
+	var buf bytes.Buffer
+	if err := bindTemplate.Execute(&buf, data); err != nil {
+		return nil, err
+	}
+	src, err := format.Source(buf.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("bind: generated invalid code: %v", err)
+	}
+	return src, nil
+}
+
+func sortedKeys(n int, each func(add func(string))) []string {
+	keys := make([]string, 0, n)
+	each(func(k string) { keys = append(keys, k) })
+	sort.Strings(keys)
+	return keys
+}
+
+func args(arguments abi.Arguments, prefix string) []tmplArg {
+	out := make([]tmplArg, len(arguments))
+	for i, arg := range arguments {
+		out[i] = tmplArg{Name: paramName(arg.Name, prefix, i), Key: arg.Name, Type: goType(arg.Type)}
+	}
+	return out
+}
+
+func argName(name, prefix string, i int) string {
+	if name == "" {
+		return fmt.Sprintf("%s%d", prefix, i)
+	}
+	return name
+}
+
+// paramName 将 ABI 参数名转换为合法且不与生成代码冲突的 Go 参数名
+func paramName(name, prefix string, i int) string {
+	name = strings.TrimLeft(argName(name, prefix, i), "_")
+	if name == "" {
+		name = fmt.Sprintf("%s%d", prefix, i)
+	}
+	r := []rune(abi.ToCamelCase(name))
+	r[0] = unicode.ToLower(r[0])
+	name = string(r)
+	if token.Lookup(name).IsKeyword() || reservedNames[name] || isPredeclared(name) {
+		name += "_"
+	}
+	return name
+}
+
+func isPredeclared(name string) bool {
+	switch name {
+	case "abi", "big", "common", "fmt", "hexutil", "strings", "tokenup_sdk", "string", "bool", "byte", "error", "len", "new", "nil", "true", "false":
+		return true
+	}
+	return false
+}
+
+func isHashedTopic(t abi.Type) bool {
+	switch t.T {
+	case abi.StringTy, abi.BytesTy, abi.SliceTy, abi.ArrayTy, abi.TupleTy:
+		return true
+	}
+	return false
+}
+
+// goType 返回 ABI 类型解码后对应的 Go 类型
+func goType(t abi.Type) string {
+	switch t.T {
+	case abi.IntTy, abi.UintTy:
+		prefix := "int"
+		if t.T == abi.UintTy {
+			prefix = "uint"
+		}
+		switch t.Size {
+		case 8, 16, 32, 64:
+			return fmt.Sprintf("%s%d", prefix, t.Size)
+		}
+		return "*big.Int"
+	case abi.BoolTy:
+		return "bool"
+	case abi.StringTy:
+		return "string"
+	case abi.AddressTy:
+		return "common.Address"
+	case abi.BytesTy:
+		return "[]byte"
+	case abi.FixedBytesTy:
+		return fmt.Sprintf("[%d]byte", t.Size)
+	case abi.HashTy:
+		return "common.Hash"
+	case abi.FunctionTy:
+		return "[24]byte"
+	case abi.SliceTy:
+		return "[]" + goType(*t.Elem)
+	case abi.ArrayTy:
+		return fmt.Sprintf("[%d]%s", t.Size, goType(*t.Elem))
+	case abi.TupleTy:
+		return t.TupleType.String()
+	}
+	return "interface{}"
+}
+
+var bindTemplate = template.Must(template.New("bind").Parse(bindSource))
@@ -0,0 +1,159 @@
+package bind_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"github.com/cblk/tokenup-sdk"
+	"github.com/cblk/tokenup-sdk/bind"
+	"github.com/cblk/tokenup-sdk/bind/internal/registry"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"io/ioutil"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// internal/registry 为 testdata/registry.abi 生成的代码，随包测试一起编译，生成逻辑变化时用 -update 重新生成
+const registryGolden = "internal/registry/registry.go"
+
+var update = flag.Bool("update", false, "rewrite "+registryGolden)
+
+func TestGenerate(t *testing.T) {
+	abiJSON, err := ioutil.ReadFile("testdata/registry.abi")
+	if err != nil {
+		t.Fatal(err)
+	}
+	src, err := bind.Generate(bind.Config{Package: "registry", Type: "Registry", ABI: string(abiJSON)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if *update {
+		if err := ioutil.WriteFile(registryGolden, src, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	golden, err := ioutil.ReadFile(registryGolden)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(src, golden) {
+		t.Fatalf("generated code differs from %s, run go test ./bind -run TestGenerate -update", registryGolden)
+	}
+	code := string(src)
+	for _, want := range []string{
+		"package registry",
+		// 关键字参数名与生成代码的局部变量名加下划线，匿名参数按序号命名
+		"func (c *Registry) Get(type_ *big.Int, arg1 [32]byte) (out0 struct {",
+		// payable 方法带 txValue，重载方法按 abi 的命名加序号
+		"func (c *Registry) Deposit(txFrom string, txValue *big.Int, to common.Address) (tokenup_sdk.TransactResponse, error)",
+		"func (c *Registry) Deposit0(txFrom string, txValue *big.Int, to common.Address, data []byte) (tokenup_sdk.TransactResponse, error)",
+		"func (c *Registry) Set(txFrom string, err_ [2]uint8) (tokenup_sdk.TransactResponse, error)",
+		// indexed string 在事件中为哈希，过滤时传原文
+		"Name common.Hash",
+		"func (c *Registry) FilterRegistered(fromBlock, toBlock int64, name []string, id []uint64) ([]RegistryRegistered, error)",
+		"func (c *Registry) ParseRegistered(e tokenup_sdk.Event) (*RegistryRegistered, error)",
+	} {
+		if !strings.Contains(code, want) {
+			t.Errorf("generated code is missing %q", want)
+		}
+	}
+
+	if _, err := bind.Generate(bind.Config{Package: "token", Type: "ERC20", ABI: tokenup_sdk.ERC20ABI}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := bind.Generate(bind.Config{Package: "token", Type: "bad-name", ABI: tokenup_sdk.ERC20ABI}); err == nil {
+		t.Fatal("invalid type name accepted")
+	}
+}
+
+func TestGeneratedBinding(t *testing.T) {
+	parsed, err := abi.JSON(strings.NewReader(registry.RegistryABI))
+	if err != nil {
+		t.Fatal(err)
+	}
+	owner := common.HexToAddress("0xdF0F1b2Fa2992247ffC68790B20a3Df1E7514B68")
+	getResult, err := parsed.Methods["get"].Outputs.Pack(struct {
+		Owner  common.Address
+		Amount *big.Int
+	}{owner, big.NewInt(42)}, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tags := [][32]byte{{1}, {2}}
+	data, err := parsed.Events["Registered"].Inputs.NonIndexed().Pack(tags)
+	if err != nil {
+		t.Fatal(err)
+	}
+	event := tokenup_sdk.Event{
+		Address: "0x1111111111111111111111111111111111111111",
+		Data:    hexutil.Encode(data),
+		Topics: strings.Join([]string{
+			parsed.Events["Registered"].ID.Hex(),
+			crypto.Keccak256Hash([]byte("alice")).Hex(),
+			common.BigToHash(big.NewInt(9)).Hex(),
+		}, ","),
+	}
+	var (
+		mu      sync.Mutex
+		queries []tokenup_sdk.QueryRequest
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		var data interface{}
+		switch r.URL.Path {
+		case "/v1/tx/call":
+			data = hexutil.Encode(getResult)
+		case "/v1/event/query":
+			var req tokenup_sdk.QueryRequest
+			_ = json.NewDecoder(r.Body).Decode(&req)
+			queries = append(queries, req)
+			data = []tokenup_sdk.Event{event}
+		}
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"message": "success", "data": data})
+	}))
+	defer srv.Close()
+	client, err := tokenup_sdk.NewClient(tokenup_sdk.NodeConfig{NodeUrl: srv.URL}, tokenup_sdk.Authorize{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	reg, err := registry.NewRegistry(client, event.Address)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// tuple 输出需要与生成代码中的匿名结构体类型一致
+	out0, out1, err := reg.Get(big.NewInt(1), [32]byte{})
+	if err != nil || out0.Owner != owner || out0.Amount.Int64() != 42 || !out1 {
+		t.Fatalf("get %+v %v %v", out0, out1, err)
+	}
+
+	parsedEvent, err := reg.ParseRegistered(event)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if parsedEvent.Name != crypto.Keccak256Hash([]byte("alice")) || parsedEvent.Id != 9 || len(parsedEvent.Tags) != 2 || parsedEvent.Tags[1] != tags[1] {
+		t.Fatalf("parsed %+v", parsedEvent)
+	}
+	if _, err := reg.ParseRegistered(tokenup_sdk.Event{Topics: common.Hash{}.Hex()}); err != tokenup_sdk.ErrUnknownEvent {
+		t.Fatalf("unknown event: %v", err)
+	}
+
+	logs, err := reg.FilterRegistered(1, 10, []string{"alice"}, nil)
+	if err != nil || len(logs) != 1 || logs[0].Id != 9 {
+		t.Fatalf("filter %+v %v", logs, err)
+	}
+	mu.Lock()
+	q := queries[0]
+	mu.Unlock()
+	if len(q.Topics) < 2 || q.Topics[1][0] != crypto.Keccak256Hash([]byte("alice")).Hex() {
+		t.Fatalf("query topics %v", q.Topics)
+	}
+}
@@ -0,0 +1,174 @@
+package bind
+
+const bindSource = `// Code generated by tokenup-abigen. DO NOT EDIT.
+
+package {{.Package}}
+
+import (
+	"fmt"
+	"github.com/cblk/tokenup-sdk"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"math/big"
+	"strings"
+)
+
+var (
+	_ = fmt.Errorf
+	_ = big.NewInt
+	_ = common.Big1
+)
+
+// {{.Type}}ABI 合约 ABI
+const {{.Type}}ABI = {{.ABI}}
+
+// {{.Type}} 绑定到合约地址的客户端，只读方法通过 Client.CallData 调用，其他方法通过 Client.SendTx 发送交易
+type {{.Type}} struct {
+	client   *tokenup_sdk.Client
+	address  string
+	callFrom string
+	abi      abi.ABI
+}
+
+// New{{.Type}} 创建绑定到 address 的合约客户端
+func New{{.Type}}(client *tokenup_sdk.Client, address string) (*{{.Type}}, error) {
+	if !common.IsHexAddress(address) {
+		return nil, fmt.Errorf("invalid contract address %q", address)
+	}
+	parsed, err := abi.JSON(strings.NewReader({{.Type}}ABI))
+	if err != nil {
+		return nil, err
+	}
+	return &{{.Type}}{client: client, address: address, abi: parsed}, nil
+}
+
+// WithCallFrom 返回只读调用使用 from 作为发送方的副本，默认为零地址
+func (c *{{.Type}}) WithCallFrom(from string) *{{.Type}} {
+	cp := *c
+	cp.callFrom = from
+	return &cp
+}
+
+func (c *{{.Type}}) call(method string, args ...interface{}) ([]interface{}, error) {
+	data, err := c.abi.Pack(method, args...)
+	if err != nil {
+		return nil, err
+	}
+	from := c.callFrom
+	if from == "" {
+		from = tokenup_sdk.ZeroAddress
+	}
+	raw, err := c.client.CallData(tokenup_sdk.CallRequest{
+		From:   from,
+		To:     c.address,
+		Data:   hexutil.Encode(data),
+		Method: method,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return c.abi.Methods[method].Outputs.UnpackValues(raw)
+}
+
+func (c *{{.Type}}) transact(from string, value *big.Int, method string, args ...interface{}) (tokenup_sdk.TransactResponse, error) {
+	data, err := c.abi.Pack(method, args...)
+	if err != nil {
+		return tokenup_sdk.TransactResponse{}, err
+	}
+	req := tokenup_sdk.TransactRequest{
+		From: from,
+		To:   c.address,
+		Data: hexutil.Encode(data),
+	}
+	if value != nil {
+		req.Value = hexutil.EncodeBig(value)
+	}
+	return c.client.SendTx(req)
+}
+
+{{range .Calls}}
+// {{.Name}} 调用只读方法 {{.Sig}}
+func (c *{{$.Type}}) {{.Name}}({{range $i, $a := .Inputs}}{{if $i}}, {{end}}{{$a.Name}} {{$a.Type}}{{end}}) ({{range .Outputs}}{{.Name}} {{.Type}}, {{end}}err error) {
+	{{if .Outputs}}values{{else}}_{{end}}, err := c.call("{{.AbiName}}"{{range .Inputs}}, {{.Name}}{{end}})
+	if err != nil {
+		return
+	}
+{{- range $i, $o := .Outputs}}
+	{{$o.Name}} = values[{{$i}}].({{$o.Type}})
+{{- end}}
+	return
+}
+{{end}}
+{{- range .Sends}}
+// {{.Name}} 由 txFrom 发送调用 {{.Sig}} 的交易{{if .Payable}}，txValue 为随交易发送的以太数量(Wei)，可以为 nil{{end}}
+func (c *{{$.Type}}) {{.Name}}(txFrom string{{if .Payable}}, txValue *big.Int{{end}}{{range .Inputs}}, {{.Name}} {{.Type}}{{end}}) (tokenup_sdk.TransactResponse, error) {
+	return c.transact(txFrom, {{if .Payable}}txValue{{else}}nil{{end}}, "{{.AbiName}}"{{range .Inputs}}, {{.Name}}{{end}})
+}
+{{end}}
+{{- range .Events}}
+// {{$.Type}}{{.Name}} 解码后的 {{.Sig}} 事件
+type {{$.Type}}{{.Name}} struct {
+{{- range .Fields}}
+	{{.Name}} {{.Type}}
+{{- end}}
+	Raw tokenup_sdk.Event
+}
+
+// Filter{{.Name}} 通过 EventQuery 查询区块范围内的 {{.Name}} 事件，indexed 参数为空时不按该参数过滤
+func (c *{{$.Type}}) Filter{{.Name}}(fromBlock, toBlock int64{{range .Indexed}}, {{.Name}} []{{.Type}}{{end}}) ([]{{$.Type}}{{.Name}}, error) {
+	query := [][]interface{}{ {c.abi.Events["{{.AbiName}}"].ID} }
+{{- range .Indexed}}
+	var {{.Name}}Rule []interface{}
+	for _, v := range {{.Name}} {
+		{{.Name}}Rule = append({{.Name}}Rule, v)
+	}
+	query = append(query, {{.Name}}Rule)
+{{- end}}
+	topics, err := abi.MakeTopics(query...)
+	if err != nil {
+		return nil, err
+	}
+	res, err := c.client.EventQuery(tokenup_sdk.QueryRequest{
+		FromBlock: fromBlock,
+		ToBlock:   toBlock,
+		Addresses: []string{c.address},
+		Topics:    tokenup_sdk.HexTopics(topics),
+	})
+	if err != nil {
+		return nil, err
+	}
+	var out []{{$.Type}}{{.Name}}
+	for _, e := range res.Data {
+		ev, err := c.Parse{{.Name}}(e)
+		if err == tokenup_sdk.ErrUnknownEvent {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, *ev)
+	}
+	return out, nil
+}
+
+// Parse{{.Name}} 解码 EventQuery 返回的 {{.Name}} 事件，其他事件返回 tokenup_sdk.ErrUnknownEvent
+func (c *{{$.Type}}) Parse{{.Name}}(e tokenup_sdk.Event) (*{{$.Type}}{{.Name}}, error) {
+	d, err := tokenup_sdk.DecodeLog(c.abi, e.Log())
+	if err != nil {
+		return nil, err
+	}
+	if d.Event != "{{.AbiName}}" {
+		return nil, tokenup_sdk.ErrUnknownEvent
+	}
+	ev := &{{$.Type}}{{.Name}}{Raw: e}
+{{- range .Fields}}
+	if v, ok := d.Args["{{.Key}}"].({{.Type}}); ok {
+		ev.{{.Name}} = v
+	} else {
+		return nil, fmt.Errorf("{{$.Type}}: unexpected type %T for {{.Key}}", d.Args["{{.Key}}"])
+	}
+{{- end}}
+	return ev, nil
+}
+{{end}}`
@@ -0,0 +1,224 @@
+package tokenup_sdk
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/url"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// CanonicalVersion 签名原文规范化规则的版本号，规则变化时递增
+//
+// 规范化规则(版本 1)：
+//
+//  1. 输入为结构体、结构体指针或键为字符串的 map。结构体中只有带 sign 标签的导出字段参与签名，
+//     标签值的第一段为字段名，sign:"-" 表示忽略；没有 sign 标签的匿名嵌入结构体按字段展开到上一层。
+//  2. 每个叶子值输出一个 key=value 对，key 为从根开始的字段名路径：
+//     嵌套结构体与 map 用 "." 连接(a.b)，切片与数组的每个元素使用 key[] (a[]=1&a[]=2)。
+//  3. 叶子值格式：
+//     string 原样；bool 为 true/false；整数为十进制；
+//     float32/float64 为 strconv.FormatFloat(v, 'E', -1, bits)，例如 1.5E+00；
+//     big.Int 为十进制；json.Number 为整数时原样，否则按 float64 规则格式化；
+//     time.Time 为 Unix 秒；[]byte 与其它切片相同，每个字节按十进制输出一个 key[] (raw[]=173&raw[]=222)。
+//     所有叶子值再经过 url.QueryEscape 转义，key 不转义。
+//  4. nil 指针、nil 接口值、空切片与空 map 不输出任何 key=value 对。
+//  5. 其它类型(chan、func、complex、非字符串键的 map 等)返回错误。
+//  6. 所有 key=value 对按字节序升序排序后用 "&" 连接。
+const CanonicalVersion = 1
+
+var (
+	bigIntType     = reflect.TypeOf(big.Int{})
+	timeType       = reflect.TypeOf(time.Time{})
+	jsonNumberType = reflect.TypeOf(json.Number(""))
+)
+
+type Dec []string
+
+func (p Dec) Len() int           { return len(p) }
+func (p Dec) Less(i, j int) bool { return p[i] < p[j] }
+func (p Dec) Swap(i, j int)      { p[i], p[j] = p[j], p[i] }
+
+// EncodeString 返回 o 的签名原文，遇到不支持的类型时返回空字符串
+//
+// Deprecated: 空字符串同样可以被签名，错误会被掩盖，使用 CanonicalString。
+func EncodeString(o interface{}) string {
+	s, _ := CanonicalString(o)
+	return s
+}
+
+// CanonicalString 按 CanonicalVersion 规则生成 o 的签名原文
+func CanonicalString(o interface{}) (string, error) {
+	v := reflect.ValueOf(o)
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return "", fmt.Errorf("canonical: nil %s", v.Kind())
+		}
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct && v.Kind() != reflect.Map {
+		return "", fmt.Errorf("canonical: unsupported top-level type %s", v.Type())
+	}
+	var dec Dec
+	if err := encodeValue(&dec, "", v); err != nil {
+		return "", err
+	}
+	sort.Stable(dec)
+	return strings.Join(dec, "&"), nil
+}
+
+func encodeValue(dec *Dec, key string, v reflect.Value) error {
+	if !v.IsValid() {
+		return nil
+	}
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if v.IsNil() {
+			return nil
+		}
+		return encodeValue(dec, key, v.Elem())
+	case reflect.Struct:
+		switch v.Type() {
+		case bigIntType, timeType:
+			if !v.CanInterface() {
+				return fmt.Errorf("canonical: %s: unexported %s field", key, v.Type())
+			}
+		}
+		switch v.Type() {
+		case bigIntType:
+			b := v.Interface().(big.Int)
+			return appendPair(dec, key, b.String())
+		case timeType:
+			return appendPair(dec, key, strconv.FormatInt(v.Interface().(time.Time).Unix(), 10))
+		}
+		return encodeStruct(dec, key, v)
+	case reflect.Map:
+		if v.Type().Key().Kind() != reflect.String {
+			return fmt.Errorf("canonical: %s: unsupported map key type %s", key, v.Type().Key())
+		}
+		iter := v.MapRange()
+		for iter.Next() {
+			if err := encodeValue(dec, joinKey(key, iter.Key().String()), iter.Value()); err != nil {
+				return err
+			}
+		}
+		return nil
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			if err := encodeValue(dec, key+"[]", v.Index(i)); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	s, err := scalarString(v)
+	if err != nil {
+		return fmt.Errorf("canonical: %s: %v", key, err)
+	}
+	return appendPair(dec, key, s)
+}
+
+// fieldPlan 结构体中参与签名的字段，name 为空表示需要展开的匿名嵌入结构体
+type fieldPlan struct {
+	index int
+	name  string
+}
+
+// structPlans 缓存每个结构体类型的字段计划，避免每次签名都解析标签
+var structPlans sync.Map
+
+func planFor(t reflect.Type) []fieldPlan {
+	if plan, ok := structPlans.Load(t); ok {
+		return plan.([]fieldPlan)
+	}
+	var plan []fieldPlan
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if f.PkgPath != "" && !f.Anonymous {
+			continue
+		}
+		tag, ok := f.Tag.Lookup("sign")
+		if !ok {
+			if f.Anonymous && indirectType(f.Type).Kind() == reflect.Struct {
+				plan = append(plan, fieldPlan{index: i})
+			}
+			continue
+		}
+		name := strings.Split(tag, ",")[0]
+		if name == "-" || name == "" {
+			continue
+		}
+		plan = append(plan, fieldPlan{index: i, name: name})
+	}
+	structPlans.Store(t, plan)
+	return plan
+}
+
+func encodeStruct(dec *Dec, prefix string, v reflect.Value) error {
+	for _, f := range planFor(v.Type()) {
+		key := prefix
+		if f.name != "" {
+			key = joinKey(prefix, f.name)
+		}
+		if err := encodeValue(dec, key, v.Field(f.index)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func scalarString(v reflect.Value) (string, error) {
+	if v.Type() == jsonNumberType {
+		n := v.String()
+		if _, err := strconv.ParseInt(n, 10, 64); err == nil {
+			return n, nil
+		}
+		if _, ok := new(big.Int).SetString(n, 10); ok {
+			return n, nil
+		}
+		f, err := strconv.ParseFloat(n, 64)
+		if err != nil {
+			return "", err
+		}
+		return strconv.FormatFloat(f, 'E', -1, 64), nil
+	}
+	switch v.Kind() {
+	case reflect.String:
+		return v.String(), nil
+	case reflect.Bool:
+		return strconv.FormatBool(v.Bool()), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(v.Int(), 10), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return strconv.FormatUint(v.Uint(), 10), nil
+	case reflect.Float32:
+		return strconv.FormatFloat(v.Float(), 'E', -1, 32), nil
+	case reflect.Float64:
+		return strconv.FormatFloat(v.Float(), 'E', -1, 64), nil
+	}
+	return "", fmt.Errorf("unsupported type %s", v.Type())
+}
+
+func appendPair(dec *Dec, key, value string) error {
+	*dec = append(*dec, key+"="+url.QueryEscape(value))
+	return nil
+}
+
+func joinKey(prefix, name string) string {
+	if prefix == "" {
+		return name
+	}
+	return prefix + "." + name
+}
+
+func indirectType(t reflect.Type) reflect.Type {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return t
+}
@@ -0,0 +1,138 @@
+package tokenup_sdk_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"github.com/cblk/tokenup-sdk"
+	"io/ioutil"
+	"math/big"
+	"testing"
+	"time"
+)
+
+func TestCanonicalVectors(t *testing.T) {
+	raw, err := ioutil.ReadFile("testdata/canonical_vectors.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var vectors struct {
+		Version int `json:"version"`
+		Cases   []struct {
+			Name      string          `json:"name"`
+			Fields    json.RawMessage `json:"fields"`
+			Canonical string          `json:"canonical"`
+		} `json:"cases"`
+	}
+	if err := json.Unmarshal(raw, &vectors); err != nil {
+		t.Fatal(err)
+	}
+	if vectors.Version != tokenup_sdk.CanonicalVersion {
+		t.Fatalf("vectors version %d, want %d", vectors.Version, tokenup_sdk.CanonicalVersion)
+	}
+	for _, c := range vectors.Cases {
+		var fields map[string]interface{}
+		dec := json.NewDecoder(bytes.NewReader(c.Fields))
+		dec.UseNumber()
+		if err := dec.Decode(&fields); err != nil {
+			t.Fatalf("%s: %v", c.Name, err)
+		}
+		got, err := tokenup_sdk.CanonicalString(fields)
+		if err != nil {
+			t.Errorf("%s: %v", c.Name, err)
+			continue
+		}
+		if got != c.Canonical {
+			t.Errorf("%s:\n got %s\nwant %s", c.Name, got, c.Canonical)
+		}
+	}
+}
+
+func TestCanonicalString_Types(t *testing.T) {
+	type item struct {
+		A string `sign:"a"`
+		B int    `sign:"b"`
+	}
+	type inner struct {
+		Amount string `sign:"amount"`
+	}
+	type embedded struct {
+		Nonce string `sign:"nonce"`
+	}
+	nonce := "n"
+	req := struct {
+		embedded
+		Ignored   string            `json:"ignored"`
+		Skipped   string            `sign:"-"`
+		Ptr       *string           `sign:"ptr"`
+		NilPtr    *string           `sign:"nil_ptr"`
+		Inner     inner             `sign:"inner"`
+		Counts    map[string]uint64 `sign:"counts"`
+		Items     []item            `sign:"items"`
+		Value     *big.Int          `sign:"value"`
+		Number    json.Number       `sign:"number"`
+		Time      time.Time         `sign:"time"`
+		Rate      float32           `sign:"rate"`
+		Raw       []byte            `sign:"raw"`
+		Timestamp int64             `sign:"timestamp,omitempty"`
+	}{
+		embedded:  embedded{Nonce: "1"},
+		Ignored:   "x",
+		Skipped:   "x",
+		Ptr:       &nonce,
+		Inner:     inner{Amount: "10"},
+		Counts:    map[string]uint64{"b": 2, "a": 1},
+		Items:     []item{{A: "x", B: 1}, {A: "y", B: 2}},
+		Value:     big.NewInt(1000000000000000000),
+		Number:    json.Number("42"),
+		Time:      time.Unix(1600000000, 0),
+		Rate:      0.1,
+		Raw:       []byte{0xde, 0xad},
+		Timestamp: 1600000000,
+	}
+	want := "counts.a=1&counts.b=2&inner.amount=10&items[].a=x&items[].a=y&items[].b=1&items[].b=2" +
+		"&nonce=1&number=42&ptr=n&rate=1E-01&raw[]=173&raw[]=222&time=1600000000&timestamp=1600000000&value=1000000000000000000"
+	got, err := tokenup_sdk.CanonicalString(&req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != want {
+		t.Errorf("\n got %s\nwant %s", got, want)
+	}
+
+	for name, bad := range map[string]interface{}{
+		"chan": struct {
+			C chan int `sign:"c"`
+		}{C: make(chan int)},
+		"complex": struct {
+			C complex64 `sign:"c"`
+		}{},
+		"int map": struct {
+			M map[int]string `sign:"m"`
+		}{M: map[int]string{1: "a"}},
+		"scalar": "plain string",
+	} {
+		if _, err := tokenup_sdk.CanonicalString(bad); err == nil {
+			t.Errorf("%s: expected error", name)
+		}
+	}
+}
+
+func TestCanonicalString_ProxySignSafe(t *testing.T) {
+	ps := tokenup_sdk.ProxySignSafe{
+		SignedEnvelope: tokenup_sdk.SignedEnvelope{
+			AppId:     "app",
+			AppKey:    "key",
+			Nonce:     "123",
+			Timestamp: 1600000000,
+			Signature: "ignored",
+		},
+		Address: "0xabc",
+		Data:    "0x01",
+		Extras:  "tokenup-sdk",
+		OrderID: "o1",
+	}
+	want := "address=0xabc&app_id=app&app_key=key&data=0x01&extras=tokenup-sdk&nonce=123&order_id=o1&timestamp=1600000000"
+	if got := tokenup_sdk.EncodeString(&ps); got != want {
+		t.Errorf("\n got %s\nwant %s", got, want)
+	}
+}
@@ -1,15 +1,19 @@
 package tokenup_sdk
 
 import (
+	"context"
+	"crypto"
 	"errors"
 	"fmt"
 	"github.com/ethereum/go-ethereum/accounts/abi"
 	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/guonaihong/gout"
 	"github.com/pborman/uuid"
+	"math/big"
 	"reflect"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 )
 
@@ -25,6 +29,8 @@ type Authorize struct {
 	PrivateKey             string
 	CallBackPartyPublicKey string
 	SignerVersion          string
+	// Signer 不为 nil 时用于签名请求，PrivateKey 可以留空
+	Signer crypto.Signer
 }
 type NodeConfig struct {
 	NodeUrl       string
@@ -33,29 +39,75 @@ type NodeConfig struct {
 	GasPriceMax   int64
 	NodeVersion   string
 	NodeNotifyUrl string
+	// GasStrategy 为 nil 时直接使用节点建议的 gas price
+	GasStrategy GasStrategy
+	// ReplaceBumpPercent SpeedUp/Cancel 时新 gas price 相对原交易的最小涨幅，默认10
+	ReplaceBumpPercent int64
+	// GasLimitMargin 调用方未指定 GasLimit 时在节点估算值上增加的余量
+	GasLimitMargin GasLimitMargin
+	// MaxFee 单笔交易 GasLimit * GasPrice 的上限(Wei)，为 nil 时使用 FeeLimit(Gwei)
+	MaxFee *big.Int
+	// MaxValue 单笔交易 Value 的上限(Wei)，为 nil 时不限制
+	MaxValue *big.Int
 }
 
 type Client struct {
 	NodeConfig
 	Authorize
+	CallbackConfig
+	// Keys 为签名与验签使用的已解析密钥，为 nil 时由 Authorize 中的密钥生成
+	Keys *KeyStore
+	// Clock 用于补偿签名请求 timestamp 的时钟偏差，为 nil 时使用默认配置
+	Clock *SkewClock
+	// Nonces 不为 nil 时 SendTx 在本地分配 nonce
+	Nonces *NonceManager
+
+	mu           sync.Mutex
+	replacements *txLineage
 }
 
-func Init(c *Client) {
-	if c != nil {
-		client = c
-		if client.GasPriceMin == 0 {
-			client.GasPriceMin = 2000000000 // 2 Gwei
-		}
-		if client.GasPriceMax == 0 {
-			client.GasPriceMax = 30000000000 // 30 Gwei
-		}
-		if client.FeeLimit == 0 {
-			client.FeeLimit = 50000000 // 0.05 Ether
-		}
-		if client.NodeVersion == "" {
-			client.NodeVersion = "v1"
-		}
+// NewClient 创建 Client 并解析 Authorize 中的密钥，密钥无效时返回错误
+func NewClient(node NodeConfig, auth Authorize) (*Client, error) {
+	c := &Client{NodeConfig: node, Authorize: auth}
+	if err := c.setup(); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// Init 初始化全局 Client，密钥无效时返回错误且不替换全局 Client
+func Init(c *Client) error {
+	if c == nil {
+		return nil
+	}
+	if err := c.setup(); err != nil {
+		return err
 	}
+	client = c
+	return nil
+}
+
+func (client *Client) setup() error {
+	if client.GasPriceMin == 0 {
+		client.GasPriceMin = 2000000000 // 2 Gwei
+	}
+	if client.GasPriceMax == 0 {
+		client.GasPriceMax = 30000000000 // 30 Gwei
+	}
+	if client.FeeLimit == 0 {
+		client.FeeLimit = 50000000 // 0.05 Ether
+	}
+	if client.NodeVersion == "" {
+		client.NodeVersion = "v1"
+	}
+	if client.CallbackWindow == 0 {
+		client.CallbackWindow = defaultCallbackWindow
+	}
+	if client.NonceCache == nil {
+		client.NonceCache = NewLRUNonceCache(defaultNonceCacheSize)
+	}
+	_, err := client.keyStore()
+	return err
 }
 
 func GetClient() *Client {
@@ -65,8 +117,28 @@ func GetClient() *Client {
 	return client
 }
 
+// NodeError 节点接口返回的非200响应
+type NodeError struct {
+	Code    int
+	Message string
+}
+
+func (e *NodeError) Error() string {
+	return fmt.Sprintf("%d-%s", e.Code, e.Message)
+}
+
+func isNodeError(err error) bool {
+	_, ok := err.(*NodeError)
+	return ok
+}
+
 func (client *Client) SignSync(signSource SignSource, timeoutSeconds int) (string, string, error) {
-	result, err := client.SignHash(signSource)
+	return client.signSync(context.Background(), signSource, timeoutSeconds)
+}
+
+// signSync SignSync 的实现，ctx 结束时停止轮询并中止正在进行的请求
+func (client *Client) signSync(ctx context.Context, signSource SignSource, timeoutSeconds int) (string, string, error) {
+	result, err := client.signHash(ctx, signSource)
 	if err != nil {
 		return "", "", err
 	}
@@ -74,10 +146,12 @@ func (client *Client) SignSync(signSource SignSource, timeoutSeconds int) (strin
 	timeout := time.After(time.Duration(timeoutSeconds) * time.Second)
 	for {
 		select {
+		case <-ctx.Done():
+			return "", requestId, ctx.Err()
 		case <-timeout:
 			return "", requestId, errors.New("timeout signer request")
 		case <-time.After(200 * time.Millisecond):
-			signerResult, err := client.OnTracing(requestId)
+			signerResult, err := client.onTracing(ctx, requestId)
 			if err != nil {
 				return "", requestId, err
 			}
@@ -89,6 +163,10 @@ func (client *Client) SignSync(signSource SignSource, timeoutSeconds int) (strin
 }
 
 func (client *Client) SignHash(signSource SignSource) (Result, error) {
+	return client.signHash(context.Background(), signSource)
+}
+
+func (client *Client) signHash(ctx context.Context, signSource SignSource) (Result, error) {
 	var ps ProxySignSafe
 	structCopy(&signSource, &ps)
 	url := client.SignerUrl
@@ -96,7 +174,7 @@ func (client *Client) SignHash(signSource SignSource) (Result, error) {
 		url += "/v2.0.0"
 	}
 	url += "/vendor/proxy/sign_hash"
-	return client.signerPost(url, &ps)
+	return client.signerPost(ctx, url, &ps)
 }
 
 func (client *Client) BatchSignHash(signSource SignSource) (Result, error) {
@@ -107,33 +185,52 @@ func (client *Client) BatchSignHash(signSource SignSource) (Result, error) {
 		url += "/batch_sign"
 	}
 	url += "/vendor/proxy/pending_sign_hash"
-	return client.signerPost(url, &ps)
+	return client.signerPost(context.Background(), url, &ps)
 }
 
 func (client *Client) ValidReceivedCallBack(confirm interface{}, message string) (ReceivedConfirm, error) {
 	signature := reflect.ValueOf(confirm).Elem().FieldByName("Signature").Interface().(string)
 	nonce := reflect.ValueOf(confirm).Elem().FieldByName("Nonce").Interface().(string)
+	var keyId string
+	if kv := reflect.ValueOf(confirm).Elem().FieldByName("KeyId"); kv.IsValid() && kv.Kind() == reflect.String {
+		keyId = kv.String()
+	}
 	reflect.ValueOf(confirm).Elem().FieldByName("Received").SetMapIndex(reflect.ValueOf("app_key"), reflect.ValueOf(client.Authorize.AppKey))
 	timeValue := reflect.ValueOf(confirm).Elem().FieldByName("Received").MapIndex(reflect.ValueOf("timestamp"))
 	var t float64
 	_, _ = fmt.Sscanf(fmt.Sprint(timeValue.Interface()), "%e", &t)
 	reflect.ValueOf(confirm).Elem().FieldByName("Received").SetMapIndex(reflect.ValueOf("timestamp"), reflect.ValueOf(uint64(t)))
-	fmt.Printf("sdk---:%s", EncodeString(confirm))
-	err := RsaSignVerAndPublicHex([]byte(EncodeString(confirm)), signature, client.Authorize.CallBackPartyPublicKey)
+	plain, err := CanonicalString(confirm)
 	if err != nil {
 		return ReceivedConfirm{}, err
 	}
+	err = client.verifyCallback([]byte(plain), signature, keyId)
+	if err != nil {
+		return ReceivedConfirm{}, err
+	}
+	if err := client.checkReplay(nonce, int64(t)); err != nil {
+		return ReceivedConfirm{}, err
+	}
 	rc := ReceivedConfirm{
 		Nonce:   nonce,
 		Message: message,
 		AppKey:  client.Authorize.AppKey,
 	}
-	signReceived, _ := RsaSignAndPrivate([]byte(EncodeString(rc)), client.Authorize.PrivateKey)
-	rc.Signature = signReceived
+	plain, err = CanonicalString(rc)
+	if err != nil {
+		return ReceivedConfirm{}, err
+	}
+	if rc.Signature, err = client.sign([]byte(plain)); err != nil {
+		return ReceivedConfirm{}, err
+	}
 	return rc, nil
 }
 
 func (client *Client) OnTracing(requestId string) (Result, error) {
+	return client.onTracing(context.Background(), requestId)
+}
+
+func (client *Client) onTracing(ctx context.Context, requestId string) (Result, error) {
 	traceSafe := TraceSafe{
 		RequestId: requestId,
 	}
@@ -142,7 +239,7 @@ func (client *Client) OnTracing(requestId string) (Result, error) {
 		url += "/v2.0.0"
 	}
 	url += "/vendor/status/tracing"
-	return client.signerPost(url, &traceSafe)
+	return client.signerPost(ctx, url, &traceSafe)
 }
 
 func (client *Client) GetTxStatus(requestId string) (Result, error) {
@@ -152,42 +249,44 @@ func (client *Client) GetTxStatus(requestId string) (Result, error) {
 	}
 	url += "/vendor/tx/status/" + requestId
 	var result Result
+	var header dateHeader
 	code := 0
-	if err := gout.GET(url).BindJSON(&result).Code(&code).Do(); err != nil {
+	sent := time.Now()
+	if err := gout.GET(url).BindJSON(&result).BindHeader(&header).Code(&code).Do(); err != nil {
 		return Result{}, err
 	}
+	client.clock().observeHeader(header, sent, time.Now())
 	if code != 200 {
 		return result, fmt.Errorf("%d-%s", code, result.Status.Message)
 	}
 	return result, nil
 }
 
-func (client *Client) signerPost(url string, data interface{}) (Result, error) {
-	value := reflect.ValueOf(data).Elem()
-	value.FieldByName("Timestamp").Set(reflect.ValueOf(time.Now().Unix()))
-	value.FieldByName("AppKey").Set(reflect.ValueOf(client.Authorize.AppKey))
-	nuVal := value.FieldByName("NotifyUrl")
-	if nuVal.CanSet() {
-		nuVal.Set(reflect.ValueOf(client.Authorize.NotifyUrl))
-	}
-	nonceVal := value.FieldByName("Nonce")
-	if nonceVal.CanSet() && nonceVal.Interface() == nil || nonceVal.Interface().(string) == "" {
-		nonceVal.Set(reflect.ValueOf(strconv.FormatInt(randInt64(), 10)))
+func (client *Client) signerPost(ctx context.Context, url string, data Signable) (Result, error) {
+	env := data.Envelope()
+	clock := client.clock()
+	env.Timestamp = clock.Now().Unix()
+	env.AppKey = client.Authorize.AppKey
+	env.AppId = client.Authorize.AppId
+	if env.Nonce == "" {
+		env.Nonce = strconv.FormatInt(randInt64(), 10)
+	}
+	plain, err := CanonicalString(data)
+	if err != nil {
+		return Result{}, err
 	}
-	value.FieldByName("AppId").Set(reflect.ValueOf(client.Authorize.AppId))
-	var Signature string
-	var err error
-	Signature, err = RsaSignAndPrivate([]byte(EncodeString(data)), client.Authorize.PrivateKey)
+	env.Signature, err = client.sign([]byte(plain))
 	if err != nil {
 		return Result{}, err
 	}
-	signValue := value.FieldByName("Signature")
-	signValue.Set(reflect.ValueOf(Signature))
 	var result Result
+	var header dateHeader
 	code := 0
-	if err := gout.POST(url).SetJSON(data).BindJSON(&result).Code(&code).Do(); err != nil {
+	sent := time.Now()
+	if err := gout.POST(url).WithContext(ctx).SetJSON(data).BindJSON(&result).BindHeader(&header).Code(&code).Do(); err != nil {
 		return Result{}, err
 	}
+	clock.observeHeader(header, sent, time.Now())
 	if code != 200 {
 		return result, fmt.Errorf("%d-%s", code, result.Status.Message)
 	}
@@ -195,10 +294,17 @@ func (client *Client) signerPost(url string, data interface{}) (Result, error) {
 }
 
 func (client *Client) Estimate(req EstimateRequest) (EstimateResponse, error) {
+	return client.estimate(context.Background(), req)
+}
+
+func (client *Client) estimate(ctx context.Context, req EstimateRequest) (EstimateResponse, error) {
 	res := EstimateResponse{}
+	if err := validate(req); err != nil {
+		return res, err
+	}
 	url := fmt.Sprintf("%v/%v/%v", client.NodeUrl, client.NodeVersion, "tx/estimate")
 	code := 0
-	if err := gout.POST(url).SetJSON(req).BindJSON(&res).Code(&code).Do(); err != nil {
+	if err := gout.POST(url).WithContext(ctx).SetJSON(req).BindJSON(&res).Code(&code).Do(); err != nil {
 		return res, err
 	}
 	if code != 200 {
@@ -207,35 +313,118 @@ func (client *Client) Estimate(req EstimateRequest) (EstimateResponse, error) {
 	return res, nil
 }
 
+// SendTx 估算 gas、签名并发送交易，交易费用或金额超过 MaxFee/MaxValue 时在签名前返回 *FeeCapError，
+// 配置了 Nonces 且 req.Nonce 为 0 时由 NonceManager 分配 nonce，
+// 节点返回 nonce too low/too high 时重新同步 nonce 并重试一次
 func (client *Client) SendTx(req TransactRequest) (TransactResponse, error) {
-	res := TransactResponse{}
+	return client.sendTxContext(context.Background(), req)
+}
+
+// sendTxContext SendTx 的实现，ctx 结束时中止正在进行的估算、签名与广播请求
+func (client *Client) sendTxContext(ctx context.Context, req TransactRequest) (TransactResponse, error) {
+	res, err := client.sendTx(ctx, req)
+	if err != nil && req.Nonce == 0 && client.Nonces != nil && isNonceError(err) {
+		return client.sendTx(ctx, req)
+	}
+	return res, err
+}
+
+func (client *Client) sendTx(ctx context.Context, req TransactRequest) (TransactResponse, error) {
+	req, _, managed, err := client.prepareTx(ctx, req)
+	if err != nil {
+		return TransactResponse{}, err
+	}
+	// 发送交易
+	res, err := client.transact(ctx, req)
+	if managed {
+		switch {
+		case err == nil:
+			client.Nonces.Confirm(req.From, req.Nonce)
+		case isNonceError(err):
+			client.Nonces.Release(req.From, req.Nonce)
+			client.Nonces.Reset(req.From)
+		case isNodeError(err):
+			client.Nonces.Release(req.From, req.Nonce)
+		default:
+			// 网络错误时交易可能已被节点接受，重新同步而不是复用该 nonce
+			client.Nonces.Confirm(req.From, req.Nonce)
+			client.Nonces.Reset(req.From)
+		}
+	}
+	return res, err
+}
+
+// prepareTx 完成 SendTx 除广播外的所有步骤，签名服务返回的签名原样写入 req.Signature，managed 表示 nonce 由 NonceManager 分配
+func (client *Client) prepareTx(ctx context.Context, req TransactRequest) (TransactRequest, int64, bool, error) {
+	req, chainId, managed, err := client.fillTx(ctx, req)
+	if err != nil {
+		return req, 0, false, err
+	}
+	//交易数据签名
+	if err := client.signTx(ctx, &req, chainId); err != nil {
+		if managed {
+			client.Nonces.Release(req.From, req.Nonce)
+		}
+		return req, 0, false, err
+	}
+	return req, chainId, managed, nil
+}
+
+// fillTx 通过 tx/estimate 补全交易的 gas price、nonce 与 gas limit(调用方未指定时)，返回节点的 chain id
+func (client *Client) fillTx(ctx context.Context, req TransactRequest) (TransactRequest, int64, bool, error) {
+	// GasPrice、GasLimit 与 Signature 由 SDK 补全，调用方未指定时不校验
+	if err := validate(req, "GasPrice", "GasLimit", "Signature"); err != nil {
+		return req, 0, false, err
+	}
 	// 交易gas相关建议
-	estimateResponse, err := client.Estimate(EstimateRequest{
+	estimateResponse, err := client.estimate(ctx, EstimateRequest{
 		From:        req.From,
 		To:          req.To,
 		Data:        req.Data,
+		Value:       req.Value,
 		FeeLimit:    client.FeeLimit,
 		GasPriceMax: client.GasPriceMax,
 		GasPriceMin: client.GasPriceMin,
 	})
 	if err != nil {
-		return res, err
+		return req, 0, false, err
 	}
 	if req.GasPrice == "" {
-		req.GasPrice = estimateResponse.Data.GasPrice
+		if req.GasPrice, err = client.gasPrice(req.GasStrategy, estimateResponse.Data.GasPrice); err != nil {
+			return req, 0, false, err
+		}
 	}
 	if req.NotifyUrl == "" {
 		req.NotifyUrl = client.NodeNotifyUrl
 	}
-	if req.Nonce == 0 {
+	// 调用方指定的 GasLimit 保持不变
+	if req.GasLimit == "" {
+		if req.GasLimit, err = client.gasLimit(estimateResponse.Data.Gas); err != nil {
+			return req, 0, false, err
+		}
+	}
+	if err := client.checkFeeCap(req); err != nil {
+		return req, 0, false, err
+	}
+	managed := req.Nonce == 0 && client.Nonces != nil
+	if managed {
+		req.Nonce, err = client.Nonces.Acquire(req.From, func() (uint64, error) {
+			return estimateResponse.Data.Nonce, nil
+		})
+		if err != nil {
+			return req, 0, false, err
+		}
+	} else if req.Nonce == 0 {
 		req.Nonce = estimateResponse.Data.Nonce
 	}
-	req.GasLimit = estimateResponse.Data.Gas
+	return req, estimateResponse.Data.ChainId, managed, nil
+}
 
-	//交易数据签名
-	txHashData, err := req.decode(estimateResponse.Data.ChainId)
+// signTx 通过签名服务对交易的 EIP-155 哈希签名，结果写入 req.Signature
+func (client *Client) signTx(ctx context.Context, req *TransactRequest, chainId int64) error {
+	txHashData, err := req.decode(chainId)
 	if err != nil {
-		return res, err
+		return err
 	}
 	uuid.SetRand(strings.NewReader(req.From + strconv.Itoa(int(time.Now().UTC().Unix()))))
 	orderId := "sign_" + uuid.NewUUID().String() + time.Now().UTC().Format("20060102150405")
@@ -245,57 +434,83 @@ func (client *Client) SendTx(req TransactRequest) (TransactResponse, error) {
 		Extras:  "tokenup-sdk",
 		OrderID: orderId,
 	}
-	req.Signature, _, err = client.SignSync(signSource, 5)
-	if err != nil {
+	req.Signature, _, err = client.signSync(ctx, signSource, 5)
+	return err
+}
+
+func (client *Client) transact(ctx context.Context, req TransactRequest) (TransactResponse, error) {
+	res := TransactResponse{}
+	if err := validate(req); err != nil {
 		return res, err
 	}
-	// 发送交易
 	code := 0
 	url := fmt.Sprintf("%v/%v/%v", client.NodeUrl, client.NodeVersion, "tx/transact")
-	if err := gout.POST(url).SetJSON(req).BindJSON(&res).Code(&code).Do(); err != nil {
+	if err := gout.POST(url).WithContext(ctx).SetJSON(req).BindJSON(&res).Code(&code).Do(); err != nil {
 		return res, err
 	}
 	if code != 200 {
-		return res, fmt.Errorf("%d-%s", code, res.Message)
+		return res, &NodeError{Code: code, Message: res.Message}
 	}
 	return res, nil
 }
 
 func (client *Client) TxDetail(txHash string) (DetailResponse, error) {
 	res := DetailResponse{}
+	if err := validate(DetailRequest{TxHash: txHash}); err != nil {
+		return res, err
+	}
 	code := 0
 	url := fmt.Sprintf("%v/%v/%v/%v", client.NodeUrl, client.NodeVersion, "tx", txHash)
 	if err := gout.GET(url).BindJSON(&res).Code(&code).Do(); err != nil {
 		return res, err
 	}
 	if code != 200 {
-		return res, fmt.Errorf("%d-%s", code, res.Message)
+		return res, &NodeError{Code: code, Message: res.Message}
 	}
 	return res, nil
 }
 
 func (client *Client) Call(req CallRequest, abi abi.ABI, out interface{}) error {
+	data, err := client.CallData(req)
+	if err != nil {
+		return err
+	}
+	return abi.Unpack(out, req.Method, data)
+}
+
+// CallData 通过 tx/call 调用合约并返回未解码的结果
+func (client *Client) CallData(req CallRequest) ([]byte, error) {
+	return client.callData(context.Background(), req)
+}
+
+func (client *Client) callData(ctx context.Context, req CallRequest) ([]byte, error) {
 	res := CallResponse{}
+	if err := validate(req); err != nil {
+		return nil, err
+	}
 	code := 0
 	url := fmt.Sprintf("%v/%v/%v", client.NodeUrl, client.NodeVersion, "tx/call")
-	if err := gout.POST(url).SetJSON(req).BindJSON(&res).Code(&code).Do(); err != nil {
-		return err
+	if err := gout.POST(url).WithContext(ctx).SetJSON(req).BindJSON(&res).Code(&code).Do(); err != nil {
+		return nil, err
 	}
 	if code != 200 {
-		return fmt.Errorf("%d-%s", code, res.Message)
+		return nil, fmt.Errorf("%d-%s", code, res.Message)
 	}
-	data, err := hexutil.Decode(res.Data)
-	if err != nil {
-		return err
-	}
-	return abi.Unpack(out, req.Method, data)
+	return hexutil.Decode(res.Data)
 }
 
 func (client *Client) EventQuery(req QueryRequest) (QueryResponse, error) {
+	return client.eventQuery(context.Background(), req)
+}
+
+func (client *Client) eventQuery(ctx context.Context, req QueryRequest) (QueryResponse, error) {
 	res := QueryResponse{}
+	if err := validate(req); err != nil {
+		return res, err
+	}
 	url := fmt.Sprintf("%v/%v/%v", client.NodeUrl, client.NodeVersion, "event/query")
 	code := 0
-	if err := gout.POST(url).SetJSON(req).BindJSON(&res).Code(&code).Do(); err != nil {
+	if err := gout.POST(url).WithContext(ctx).SetJSON(req).BindJSON(&res).Code(&code).Do(); err != nil {
 		return res, err
 	}
 	if code != 200 {
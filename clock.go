@@ -0,0 +1,92 @@
+package tokenup_sdk
+
+import (
+	"net/http"
+	"sync"
+	"time"
+)
+
+const (
+	defaultSkewAlpha         = 0.2
+	defaultSkewWarnThreshold = 30 * time.Second
+)
+
+// SkewClock 根据服务端响应的 Date 头估计本地时钟与服务端的偏差，并对签名请求的 timestamp 进行补偿
+// Alpha 为偏差的指数平滑系数(0,1]，默认0.2；偏差绝对值超过 WarnThreshold(默认30秒)时调用 OnWarn
+type SkewClock struct {
+	Alpha         float64
+	WarnThreshold time.Duration
+	OnWarn        func(skew time.Duration)
+
+	mu      sync.Mutex
+	offset  time.Duration
+	samples int
+}
+
+type dateHeader struct {
+	Date string `header:"Date"`
+}
+
+// Now 返回补偿偏差后的当前时间
+func (c *SkewClock) Now() time.Time {
+	return time.Now().Add(c.Skew())
+}
+
+// Skew 返回估计的偏差(服务端时间 - 本地时间)
+func (c *SkewClock) Skew() time.Duration {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.offset
+}
+
+// Observe 记录一次服务端时间采样，sent/received 为本地发出请求与收到响应的时间
+func (c *SkewClock) Observe(server, sent, received time.Time) {
+	// Date 头精度为秒，取该秒的中点
+	sample := server.Add(500 * time.Millisecond).Sub(sent.Add(received.Sub(sent) / 2))
+	alpha := c.Alpha
+	if alpha <= 0 || alpha > 1 {
+		alpha = defaultSkewAlpha
+	}
+	c.mu.Lock()
+	if c.samples == 0 {
+		c.offset = sample
+	} else {
+		c.offset += time.Duration(alpha * float64(sample-c.offset))
+	}
+	c.samples++
+	skew := c.offset
+	c.mu.Unlock()
+
+	threshold := c.WarnThreshold
+	if threshold <= 0 {
+		threshold = defaultSkewWarnThreshold
+	}
+	if c.OnWarn != nil && (skew > threshold || skew < -threshold) {
+		c.OnWarn(skew)
+	}
+}
+
+func (c *SkewClock) observeHeader(h dateHeader, sent, received time.Time) {
+	if h.Date == "" {
+		return
+	}
+	server, err := http.ParseTime(h.Date)
+	if err != nil {
+		return
+	}
+	c.Observe(server, sent, received)
+}
+
+func (client *Client) clock() *SkewClock {
+	client.mu.Lock()
+	defer client.mu.Unlock()
+	if client.Clock == nil {
+		client.Clock = &SkewClock{}
+	}
+	return client.Clock
+}
+
+// ClockSkew 返回根据签名服务响应估计的本地时钟偏差(服务端时间 - 本地时间)
+func (client *Client) ClockSkew() time.Duration {
+	return client.clock().Skew()
+}
@@ -0,0 +1,49 @@
+package tokenup_sdk_test
+
+import (
+	"encoding/json"
+	"github.com/cblk/tokenup-sdk"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestClockSkew(t *testing.T) {
+	var timestamps []int64
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req tokenup_sdk.TraceSafe
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		timestamps = append(timestamps, req.Timestamp)
+		w.Header().Set("Date", time.Now().Add(time.Hour).UTC().Format(http.TimeFormat))
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"status":{"code":200,"message":"success"}}`))
+	}))
+	defer srv.Close()
+
+	priv, pub := newTestKeys(t)
+	client, err := tokenup_sdk.NewClient(tokenup_sdk.NodeConfig{}, tokenup_sdk.Authorize{
+		SignerUrl:              srv.URL,
+		PrivateKey:             priv,
+		CallBackPartyPublicKey: pub,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var warned time.Duration
+	client.Clock = &tokenup_sdk.SkewClock{OnWarn: func(skew time.Duration) { warned = skew }}
+	for i := 0; i < 2; i++ {
+		if _, err := client.OnTracing("req"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if skew := client.ClockSkew(); skew < 59*time.Minute || skew > 61*time.Minute {
+		t.Fatalf("skew = %v", skew)
+	}
+	if warned == 0 {
+		t.Fatal("skew warning not emitted")
+	}
+	if d := timestamps[1] - time.Now().Unix(); d < 3500 {
+		t.Fatalf("timestamp not compensated: %d", d)
+	}
+}
@@ -0,0 +1,54 @@
+// tokenup-abigen 根据合约 ABI 生成基于 tokenup_sdk.Client 的 Go 绑定代码
+//
+//	tokenup-abigen -abi token.abi -pkg token -type Token -out token/token.go
+package main
+
+import (
+	"flag"
+	"fmt"
+	"github.com/cblk/tokenup-sdk/bind"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"io/ioutil"
+	"os"
+)
+
+func main() {
+	abiFile := flag.String("abi", "", "合约 ABI JSON 文件，- 表示标准输入")
+	pkg := flag.String("pkg", "", "生成代码的包名")
+	typ := flag.String("type", "", "合约类型名，默认为首字母大写的包名")
+	out := flag.String("out", "", "输出文件，默认标准输出")
+	flag.Parse()
+
+	if err := run(*abiFile, *pkg, *typ, *out); err != nil {
+		fmt.Fprintln(os.Stderr, "tokenup-abigen:", err)
+		os.Exit(1)
+	}
+}
+
+func run(abiFile, pkg, typ, out string) error {
+	if abiFile == "" || pkg == "" {
+		return fmt.Errorf("-abi and -pkg are required")
+	}
+	if typ == "" {
+		typ = abi.ToCamelCase(pkg)
+	}
+	var abiJSON []byte
+	var err error
+	if abiFile == "-" {
+		abiJSON, err = ioutil.ReadAll(os.Stdin)
+	} else {
+		abiJSON, err = ioutil.ReadFile(abiFile)
+	}
+	if err != nil {
+		return err
+	}
+	src, err := bind.Generate(bind.Config{Package: pkg, Type: typ, ABI: string(abiJSON)})
+	if err != nil {
+		return err
+	}
+	if out == "" {
+		_, err = os.Stdout.Write(src)
+		return err
+	}
+	return ioutil.WriteFile(out, src, 0644)
+}
@@ -0,0 +1,134 @@
+// tokenup-offline 导出待离线签名的交易，并广播离线签名后的交易
+//
+//	tokenup-offline export -node http://node:8080 -from 0x... -to 0x... -value 0xde0b6b3a7640000 -out tx.json
+//	tokenup-offline broadcast -node http://node:8080 -in tx.json -signature 0x...
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"github.com/cblk/tokenup-sdk"
+	"io/ioutil"
+	"os"
+	"strings"
+)
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+	}
+	var err error
+	switch os.Args[1] {
+	case "export":
+		err = export(os.Args[2:])
+	case "broadcast":
+		err = broadcast(os.Args[2:])
+	default:
+		usage()
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "tokenup-offline:", err)
+		os.Exit(1)
+	}
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "usage: tokenup-offline export|broadcast [flags]")
+	os.Exit(2)
+}
+
+func export(args []string) error {
+	fs := flag.NewFlagSet("export", flag.ExitOnError)
+	node := fs.String("node", "", "节点网关地址")
+	from := fs.String("from", "", "交易发送方地址")
+	to := fs.String("to", "", "交易目标地址，创建合约时为空")
+	value := fs.String("value", "", "发送的以太数量(Wei，16进制字符串)")
+	data := fs.String("data", "", "交易数据(16进制字符串)")
+	gasPrice := fs.String("gas-price", "", "gas价格(16进制字符串)，为空时使用节点估算值")
+	memo := fs.String("memo", "", "附加在导出数据中的备注")
+	text := fs.Bool("text", false, "输出单行文本而不是 JSON")
+	out := fs.String("out", "", "输出文件，默认标准输出")
+	fs.Parse(args)
+
+	client, err := newClient(*node)
+	if err != nil {
+		return err
+	}
+	var metadata map[string]string
+	if *memo != "" {
+		metadata = map[string]string{"memo": *memo}
+	}
+	u, err := client.ExportTx(tokenup_sdk.TransactRequest{
+		From:     *from,
+		To:       *to,
+		Value:    *value,
+		Data:     *data,
+		GasPrice: *gasPrice,
+	}, metadata)
+	if err != nil {
+		return err
+	}
+	var b []byte
+	if *text {
+		s, err := u.EncodeText()
+		if err != nil {
+			return err
+		}
+		b = []byte(s + "\n")
+	} else {
+		if b, err = json.MarshalIndent(u, "", "  "); err != nil {
+			return err
+		}
+		b = append(b, '\n')
+	}
+	if *out == "" {
+		_, err = os.Stdout.Write(b)
+		return err
+	}
+	fmt.Fprintf(os.Stderr, "hash to sign: %s\n", u.Hash)
+	return ioutil.WriteFile(*out, b, 0644)
+}
+
+func broadcast(args []string) error {
+	fs := flag.NewFlagSet("broadcast", flag.ExitOnError)
+	node := fs.String("node", "", "节点网关地址")
+	in := fs.String("in", "", "export 输出的文件(JSON 或文本)，默认标准输入")
+	signature := fs.String("signature", "", "离线签名(65字节 [R || S || V] 16进制字符串)")
+	fs.Parse(args)
+
+	if *signature == "" {
+		return fmt.Errorf("-signature is required")
+	}
+	var b []byte
+	var err error
+	if *in == "" {
+		b, err = ioutil.ReadAll(os.Stdin)
+	} else {
+		b, err = ioutil.ReadFile(*in)
+	}
+	if err != nil {
+		return err
+	}
+	u, err := tokenup_sdk.DecodeUnsignedTx(string(b))
+	if err != nil {
+		return err
+	}
+	client, err := newClient(*node)
+	if err != nil {
+		return err
+	}
+	res, err := client.BroadcastSigned(u, strings.TrimSpace(*signature))
+	if err != nil {
+		return err
+	}
+	fmt.Println(res.Data.TxHash)
+	return nil
+}
+
+func newClient(node string) (*tokenup_sdk.Client, error) {
+	if node == "" {
+		return nil, fmt.Errorf("-node is required")
+	}
+	return tokenup_sdk.NewClient(tokenup_sdk.NodeConfig{NodeUrl: node}, tokenup_sdk.Authorize{})
+}
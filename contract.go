@@ -0,0 +1,187 @@
+package tokenup_sdk
+
+import (
+	"context"
+	"fmt"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"io/ioutil"
+	"math/big"
+	"reflect"
+	"strings"
+)
+
+// BoundContract 运行时绑定 ABI 与合约地址的通用合约客户端，不需要生成代码
+type BoundContract struct {
+	Address string
+	ABI     abi.ABI
+	Client  *Client
+	// CallFrom 只读调用使用的 from 地址，为空时使用零地址
+	CallFrom string
+}
+
+// NewBoundContract 由 ABI JSON 字符串创建 BoundContract
+func NewBoundContract(client *Client, address, abiJSON string) (*BoundContract, error) {
+	if msg := checkAddress(address); msg != "" {
+		return nil, fmt.Errorf("invalid contract address: %s", msg)
+	}
+	contractABI, err := abi.JSON(strings.NewReader(abiJSON))
+	if err != nil {
+		return nil, err
+	}
+	return &BoundContract{Address: address, ABI: contractABI, Client: client}, nil
+}
+
+// NewBoundContractFromFile 由 ABI JSON 文件创建 BoundContract
+func NewBoundContractFromFile(client *Client, address, path string) (*BoundContract, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return NewBoundContract(client, address, string(b))
+}
+
+// Call 调用只读方法，按 ABI 解码后按顺序返回全部输出，ctx 结束时中止正在进行的请求
+func (c *BoundContract) Call(ctx context.Context, method string, args ...interface{}) ([]interface{}, error) {
+	m, ok := c.ABI.Methods[method]
+	if !ok {
+		return nil, fmt.Errorf("method %q not found in ABI", method)
+	}
+	data, err := c.ABI.Pack(method, args...)
+	if err != nil {
+		return nil, err
+	}
+	from := c.CallFrom
+	if from == "" {
+		from = ZeroAddress
+	}
+	raw, err := c.Client.callData(ctx, CallRequest{
+		From:   from,
+		To:     c.Address,
+		Data:   hexutil.Encode(data),
+		Method: method,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return m.Outputs.UnpackValues(raw)
+}
+
+// Transact 与 SendTx 相同，由 from 发送调用 method 的交易，value 为随交易发送的以太数量(Wei)，可以为 nil，
+// ctx 结束时中止正在进行的估算、签名与广播请求
+func (c *BoundContract) Transact(ctx context.Context, from, method string, value *big.Int, args ...interface{}) (TransactResponse, error) {
+	m, ok := c.ABI.Methods[method]
+	if !ok {
+		return TransactResponse{}, fmt.Errorf("method %q not found in ABI", method)
+	}
+	if value != nil && value.Sign() > 0 && !m.IsPayable() {
+		return TransactResponse{}, fmt.Errorf("method %q is not payable", method)
+	}
+	data, err := c.ABI.Pack(method, args...)
+	if err != nil {
+		return TransactResponse{}, err
+	}
+	req := TransactRequest{
+		From: from,
+		To:   c.Address,
+		Data: hexutil.Encode(data),
+	}
+	if value != nil {
+		req.Value = hexutil.EncodeBig(value)
+	}
+	return c.Client.sendTxContext(ctx, req)
+}
+
+// FilterLogs 通过 EventQuery 查询区块范围内合约的 event 事件并解码
+// indexedArgs 按顺序对应事件的 indexed 参数：nil 表示不过滤，切片(不含 []byte)表示匹配其中任意一个值，
+// address 类型的参数可以传 0x 字符串；ctx 结束时中止正在进行的请求
+func (c *BoundContract) FilterLogs(ctx context.Context, event string, fromBlock, toBlock int64, indexedArgs ...interface{}) ([]DecodedLog, error) {
+	e, ok := c.ABI.Events[event]
+	if !ok {
+		return nil, fmt.Errorf("event %q not found in ABI", event)
+	}
+	var indexed abi.Arguments
+	for _, arg := range e.Inputs {
+		if arg.Indexed {
+			indexed = append(indexed, arg)
+		}
+	}
+	if len(indexedArgs) > len(indexed) {
+		return nil, fmt.Errorf("event %q has %d indexed arguments, got %d", event, len(indexed), len(indexedArgs))
+	}
+	query := [][]interface{}{{e.ID}}
+	for i, arg := range indexedArgs {
+		rule, err := topicRule(indexed[i], arg)
+		if err != nil {
+			return nil, err
+		}
+		query = append(query, rule)
+	}
+	topics, err := abi.MakeTopics(query...)
+	if err != nil {
+		return nil, err
+	}
+	res, err := c.Client.eventQuery(ctx, QueryRequest{
+		FromBlock: fromBlock,
+		ToBlock:   toBlock,
+		Addresses: []string{c.Address},
+		Topics:    HexTopics(topics),
+	})
+	if err != nil {
+		return nil, err
+	}
+	var logs []DecodedLog
+	for _, ev := range res.Data {
+		d, err := DecodeLog(c.ABI, ev.Log())
+		if err == ErrUnknownEvent {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		if d.Event == event {
+			logs = append(logs, d)
+		}
+	}
+	return logs, nil
+}
+
+// topicRule 将 FilterLogs 的一个 indexed 参数转换为 MakeTopics 的过滤条件
+func topicRule(arg abi.Argument, v interface{}) ([]interface{}, error) {
+	if v == nil {
+		return nil, nil
+	}
+	var values []interface{}
+	rv := reflect.ValueOf(v)
+	if rv.Kind() == reflect.Slice && rv.Type().Elem().Kind() != reflect.Uint8 {
+		for i := 0; i < rv.Len(); i++ {
+			values = append(values, rv.Index(i).Interface())
+		}
+	} else {
+		values = []interface{}{v}
+	}
+	if arg.Type.T == abi.AddressTy {
+		for i, value := range values {
+			if s, ok := value.(string); ok {
+				addr, err := parseAddress(s)
+				if err != nil {
+					return nil, fmt.Errorf("indexed argument %q: %v", arg.Name, err)
+				}
+				values[i] = addr
+			}
+		}
+	}
+	return values, nil
+}
+
+// HexTopics 将 abi.MakeTopics 的结果转换为 QueryRequest.Topics
+func HexTopics(topics [][]common.Hash) [][]string {
+	out := make([][]string, len(topics))
+	for i, rule := range topics {
+		for _, t := range rule {
+			out[i] = append(out[i], t.Hex())
+		}
+	}
+	return out
+}
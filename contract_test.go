@@ -0,0 +1,97 @@
+package tokenup_sdk_test
+
+import (
+	"context"
+	"errors"
+	"github.com/cblk/tokenup-sdk"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"math/big"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestBoundContract(t *testing.T) {
+	node, client := newFakeNode(t)
+	node.acceptTx()
+	node.reply("/v1/tx/call", hexutil.Encode(common.LeftPadBytes(big.NewInt(5).Bytes(), 32)))
+	c, err := tokenup_sdk.NewBoundContract(client, tokenAddress, tokenup_sdk.ERC20ABI)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	out, err := c.Call(ctx, "balanceOf", common.HexToAddress(node.address()))
+	if err != nil || len(out) != 1 || out[0].(*big.Int).Int64() != 5 {
+		t.Fatalf("call %v %v", out, err)
+	}
+	if _, err := c.Call(ctx, "missing"); err == nil {
+		t.Fatal("unknown method accepted")
+	}
+
+	to := common.HexToAddress("0xdF0F1b2Fa2992247ffC68790B20a3Df1E7514B68")
+	if _, err := c.Transact(ctx, node.address(), "transfer", nil, to, big.NewInt(7)); err != nil {
+		t.Fatal(err)
+	}
+	want, _ := c.ABI.Pack("transfer", to, big.NewInt(7))
+	if sent := node.sent(); len(sent) != 1 || sent[0].Data != hexutil.Encode(want) || sent[0].Value != "" {
+		t.Fatalf("sent %+v", sent)
+	}
+	if _, err := c.Transact(ctx, node.address(), "transfer", big.NewInt(1), to, big.NewInt(7)); err == nil {
+		t.Fatal("value sent to non-payable method")
+	}
+
+	value := hexutil.Encode(common.LeftPadBytes(big.NewInt(42).Bytes(), 32))
+	node.reply("/v1/event/query", []tokenup_sdk.Event{{
+		Address: tokenAddress,
+		Data:    value,
+		Topics:  strings.Join([]string{tokenup_sdk.ERC20TransferTopic, common.BytesToHash(common.HexToAddress(node.address()).Bytes()).Hex(), common.BytesToHash(to.Bytes()).Hex()}, ","),
+	}})
+	logs, err := c.FilterLogs(ctx, "Transfer", 1, 100, nil, []string{to.Hex(), tokenAddress})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(logs) != 1 || logs[0].Args["to"] != to || logs[0].Args["value"].(*big.Int).Int64() != 42 {
+		t.Fatalf("logs %+v", logs)
+	}
+	var queries []tokenup_sdk.QueryRequest
+	node.decode("/v1/event/query", &queries)
+	q := queries[0]
+	if len(q.Topics) != 3 || q.Topics[0][0] != tokenup_sdk.ERC20TransferTopic || len(q.Topics[1]) != 0 || len(q.Topics[2]) != 2 {
+		t.Fatalf("query topics %v", q.Topics)
+	}
+	if q.Topics[2][0] != common.BytesToHash(to.Bytes()).Hex() || q.FromBlock != 1 || q.ToBlock != 100 {
+		t.Fatalf("query %+v", q)
+	}
+	if _, err := c.FilterLogs(ctx, "Transfer", 1, 100, "0x01"); err == nil {
+		t.Fatal("invalid address filter accepted")
+	}
+}
+
+func TestBoundContract_ContextCancel(t *testing.T) {
+	node, client := newFakeNode(t)
+	release := make(chan struct{})
+	defer close(release)
+	block := func(string, []byte) (int, interface{}) {
+		<-release
+		return http.StatusServiceUnavailable, "released"
+	}
+	node.handle("/v1/tx/call", block)
+	node.handle("/v1/event/query", block)
+	c, err := tokenup_sdk.NewBoundContract(client, tokenAddress, tokenup_sdk.ERC20ABI)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if _, err := c.Call(ctx, "balanceOf", common.HexToAddress(node.address())); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("call: %v", err)
+	}
+	if _, err := c.FilterLogs(ctx, "Transfer", 1, 100); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("filter logs: %v", err)
+	}
+	if node.count("/v1/tx/call") != 1 {
+		t.Fatal("call cancelled before reaching the node")
+	}
+}
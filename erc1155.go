@@ -0,0 +1,163 @@
+package tokenup_sdk
+
+import (
+	"errors"
+	"github.com/ethereum/go-ethereum/common"
+	"math/big"
+)
+
+// ERC1155ABI 标准 ERC-1155 接口
+const ERC1155ABI = `[
+{"inputs":[{"name":"account","type":"address"},{"name":"id","type":"uint256"}],"name":"balanceOf","outputs":[{"name":"","type":"uint256"}],"stateMutability":"view","type":"function"},
+{"inputs":[{"name":"accounts","type":"address[]"},{"name":"ids","type":"uint256[]"}],"name":"balanceOfBatch","outputs":[{"name":"","type":"uint256[]"}],"stateMutability":"view","type":"function"},
+{"inputs":[{"name":"account","type":"address"},{"name":"operator","type":"address"}],"name":"isApprovedForAll","outputs":[{"name":"","type":"bool"}],"stateMutability":"view","type":"function"},
+{"inputs":[{"name":"from","type":"address"},{"name":"to","type":"address"},{"name":"id","type":"uint256"},{"name":"amount","type":"uint256"},{"name":"data","type":"bytes"}],"name":"safeTransferFrom","outputs":[],"stateMutability":"nonpayable","type":"function"},
+{"inputs":[{"name":"from","type":"address"},{"name":"to","type":"address"},{"name":"ids","type":"uint256[]"},{"name":"amounts","type":"uint256[]"},{"name":"data","type":"bytes"}],"name":"safeBatchTransferFrom","outputs":[],"stateMutability":"nonpayable","type":"function"},
+{"inputs":[{"name":"operator","type":"address"},{"name":"approved","type":"bool"}],"name":"setApprovalForAll","outputs":[],"stateMutability":"nonpayable","type":"function"},
+{"anonymous":false,"inputs":[{"indexed":true,"name":"operator","type":"address"},{"indexed":true,"name":"from","type":"address"},{"indexed":true,"name":"to","type":"address"},{"indexed":false,"name":"id","type":"uint256"},{"indexed":false,"name":"value","type":"uint256"}],"name":"TransferSingle","type":"event"},
+{"anonymous":false,"inputs":[{"indexed":true,"name":"operator","type":"address"},{"indexed":true,"name":"from","type":"address"},{"indexed":true,"name":"to","type":"address"},{"indexed":false,"name":"ids","type":"uint256[]"},{"indexed":false,"name":"values","type":"uint256[]"}],"name":"TransferBatch","type":"event"},
+{"anonymous":false,"inputs":[{"indexed":true,"name":"account","type":"address"},{"indexed":true,"name":"operator","type":"address"},{"indexed":false,"name":"approved","type":"bool"}],"name":"ApprovalForAll","type":"event"}
+]`
+
+var erc1155ABI = mustParseABI(ERC1155ABI)
+
+var (
+	ERC1155TransferSingleTopic = erc1155ABI.Events["TransferSingle"].ID.Hex()
+	ERC1155TransferBatchTopic  = erc1155ABI.Events["TransferBatch"].ID.Hex()
+)
+
+// ERC1155 绑定到合约地址的 ERC-1155 客户端
+type ERC1155 struct {
+	Client  *Client
+	Address string
+	// CallFrom 只读调用使用的 from 地址，为空时使用零地址
+	CallFrom string
+}
+
+func NewERC1155(client *Client, address string) *ERC1155 {
+	return &ERC1155{Client: client, Address: address}
+}
+
+// ERC1155Transfer 解码后的 TransferSingle 或 TransferBatch 事件，TransferSingle 的 Ids/Values 长度为1
+type ERC1155Transfer struct {
+	Token    string
+	Operator string
+	From     string
+	To       string
+	Ids      []*big.Int
+	Values   []*big.Int
+	Batch    bool
+	Event    Event
+}
+
+func (t *ERC1155) BalanceOf(account string, id *big.Int) (*big.Int, error) {
+	accountAddr, err := parseAddress(account)
+	if err != nil {
+		return nil, err
+	}
+	out := new(*big.Int)
+	err = t.call(out, "balanceOf", accountAddr, id)
+	return *out, err
+}
+
+// BalanceOfBatch 批量查询 accounts[i] 持有的 ids[i] 数量
+func (t *ERC1155) BalanceOfBatch(accounts []string, ids []*big.Int) ([]*big.Int, error) {
+	if len(accounts) != len(ids) {
+		return nil, errors.New("accounts and ids length mismatch")
+	}
+	addrs, err := parseAddresses(accounts...)
+	if err != nil {
+		return nil, err
+	}
+	out := new([]*big.Int)
+	err = t.call(out, "balanceOfBatch", addrs, ids)
+	return *out, err
+}
+
+func (t *ERC1155) IsApprovedForAll(account, operator string) (bool, error) {
+	addrs, err := parseAddresses(account, operator)
+	if err != nil {
+		return false, err
+	}
+	var out bool
+	err = t.call(&out, "isApprovedForAll", addrs[0], addrs[1])
+	return out, err
+}
+
+// SafeTransferFrom 由 sender(from 本身或已授权的 operator)发起，将 amount 个 id 从 from 转给 to，data 可以为 nil
+func (t *ERC1155) SafeTransferFrom(sender, from, to string, id, amount *big.Int, data []byte) (TransactResponse, error) {
+	addrs, err := parseAddresses(from, to)
+	if err != nil {
+		return TransactResponse{}, err
+	}
+	if data == nil {
+		data = []byte{}
+	}
+	return t.send(sender, "safeTransferFrom", addrs[0], addrs[1], id, amount, data)
+}
+
+// SafeBatchTransferFrom 批量转账，ids 与 amounts 一一对应
+func (t *ERC1155) SafeBatchTransferFrom(sender, from, to string, ids, amounts []*big.Int, data []byte) (TransactResponse, error) {
+	if len(ids) != len(amounts) {
+		return TransactResponse{}, errors.New("ids and amounts length mismatch")
+	}
+	addrs, err := parseAddresses(from, to)
+	if err != nil {
+		return TransactResponse{}, err
+	}
+	if data == nil {
+		data = []byte{}
+	}
+	return t.send(sender, "safeBatchTransferFrom", addrs[0], addrs[1], ids, amounts, data)
+}
+
+// SetApprovalForAll 授权或取消 operator 管理 account 的全部 token
+func (t *ERC1155) SetApprovalForAll(account, operator string, approved bool) (TransactResponse, error) {
+	operatorAddr, err := parseAddress(operator)
+	if err != nil {
+		return TransactResponse{}, err
+	}
+	return t.send(account, "setApprovalForAll", operatorAddr, approved)
+}
+
+func (t *ERC1155) call(out interface{}, method string, args ...interface{}) error {
+	return contractCall(t.Client, t.CallFrom, t.Address, erc1155ABI, out, method, args...)
+}
+
+func (t *ERC1155) send(from, method string, args ...interface{}) (TransactResponse, error) {
+	return contractSend(t.Client, from, t.Address, erc1155ABI, method, args...)
+}
+
+// DecodeERC1155Transfers 解码 EventQuery 返回的 TransferSingle 与 TransferBatch 事件，忽略其他事件
+func DecodeERC1155Transfers(events []Event) ([]ERC1155Transfer, error) {
+	var transfers []ERC1155Transfer
+	for _, e := range events {
+		d, err := DecodeLog(erc1155ABI, e.Log())
+		if err == ErrUnknownEvent {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		if d.Event != "TransferSingle" && d.Event != "TransferBatch" {
+			continue
+		}
+		tr := ERC1155Transfer{
+			Token:    e.Address,
+			Operator: d.Args["operator"].(common.Address).Hex(),
+			From:     d.Args["from"].(common.Address).Hex(),
+			To:       d.Args["to"].(common.Address).Hex(),
+			Event:    e,
+		}
+		if d.Event == "TransferSingle" {
+			tr.Ids = []*big.Int{d.Args["id"].(*big.Int)}
+			tr.Values = []*big.Int{d.Args["value"].(*big.Int)}
+		} else {
+			tr.Ids = d.Args["ids"].([]*big.Int)
+			tr.Values = d.Args["values"].([]*big.Int)
+			tr.Batch = true
+		}
+		transfers = append(transfers, tr)
+	}
+	return transfers, nil
+}
@@ -0,0 +1,73 @@
+package tokenup_sdk_test
+
+import (
+	"github.com/cblk/tokenup-sdk"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"math/big"
+	"strings"
+	"testing"
+)
+
+func TestERC1155(t *testing.T) {
+	erc1155, err := abi.JSON(strings.NewReader(tokenup_sdk.ERC1155ABI))
+	if err != nil {
+		t.Fatal(err)
+	}
+	balances, _ := erc1155.Methods["balanceOfBatch"].Outputs.Pack([]*big.Int{big.NewInt(3), big.NewInt(5)})
+	results := map[string]string{hexutil.Encode(erc1155.Methods["balanceOfBatch"].ID): hexutil.Encode(balances)}
+	node, client := newFakeNode(t)
+	node.acceptTx()
+	node.replyCalls(results)
+
+	token := tokenup_sdk.NewERC1155(client, tokenAddress)
+	got, err := token.BalanceOfBatch([]string{node.address(), node.address()}, []*big.Int{big.NewInt(1), big.NewInt(2)})
+	if err != nil || len(got) != 2 || got[0].Int64() != 3 || got[1].Int64() != 5 {
+		t.Fatalf("balances %v %v", got, err)
+	}
+	if _, err := token.BalanceOfBatch([]string{node.address()}, nil); err == nil {
+		t.Fatal("length mismatch accepted")
+	}
+
+	to := "0xdF0F1b2Fa2992247ffC68790B20a3Df1E7514B68"
+	ids := []*big.Int{big.NewInt(1), big.NewInt(2)}
+	amounts := []*big.Int{big.NewInt(10), big.NewInt(20)}
+	if _, err := token.SafeBatchTransferFrom(node.address(), node.address(), to, ids, amounts, nil); err != nil {
+		t.Fatal(err)
+	}
+	want, _ := erc1155.Pack("safeBatchTransferFrom", common.HexToAddress(node.address()), common.HexToAddress(to), ids, amounts, []byte{})
+	if sent := node.sent(); len(sent) != 1 || sent[0].Data != hexutil.Encode(want) {
+		t.Fatalf("sent %+v", sent)
+	}
+}
+
+func TestDecodeERC1155Transfers(t *testing.T) {
+	erc1155, err := abi.JSON(strings.NewReader(tokenup_sdk.ERC1155ABI))
+	if err != nil {
+		t.Fatal(err)
+	}
+	operator := common.HexToAddress("0xdF0F1b2Fa2992247ffC68790B20a3Df1E7514B68")
+	topics := func(topic0 string) string {
+		addr := common.BytesToHash(operator.Bytes()).Hex()
+		return strings.Join([]string{topic0, addr, addr, addr}, ",")
+	}
+	single, _ := erc1155.Events["TransferSingle"].Inputs.NonIndexed().Pack(big.NewInt(1), big.NewInt(10))
+	batch, _ := erc1155.Events["TransferBatch"].Inputs.NonIndexed().Pack([]*big.Int{big.NewInt(1), big.NewInt(2)}, []*big.Int{big.NewInt(10), big.NewInt(20)})
+	approval, _ := erc1155.Events["ApprovalForAll"].Inputs.NonIndexed().Pack(true)
+	events := []tokenup_sdk.Event{
+		{Address: tokenAddress, Topics: topics(tokenup_sdk.ERC1155TransferSingleTopic), Data: hexutil.Encode(single)},
+		{Address: tokenAddress, Topics: topics(tokenup_sdk.ERC1155TransferBatchTopic), Data: hexutil.Encode(batch)},
+		{Address: tokenAddress, Topics: strings.Join(strings.Split(topics(erc1155.Events["ApprovalForAll"].ID.Hex()), ",")[:3], ","), Data: hexutil.Encode(approval)},
+	}
+	transfers, err := tokenup_sdk.DecodeERC1155Transfers(events)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(transfers) != 2 || transfers[0].Batch || transfers[0].Values[0].Int64() != 10 {
+		t.Fatalf("transfers %+v", transfers)
+	}
+	if !transfers[1].Batch || len(transfers[1].Ids) != 2 || transfers[1].Values[1].Int64() != 20 || transfers[1].Operator != operator.Hex() {
+		t.Fatalf("batch %+v", transfers[1])
+	}
+}
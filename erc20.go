@@ -0,0 +1,310 @@
+package tokenup_sdk
+
+import (
+	"errors"
+	"fmt"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"math/big"
+	"strings"
+	"sync"
+)
+
+// ERC20ABI 标准 ERC-20 接口
+const ERC20ABI = `[
+{"constant":true,"inputs":[],"name":"name","outputs":[{"name":"","type":"string"}],"stateMutability":"view","type":"function"},
+{"constant":true,"inputs":[],"name":"symbol","outputs":[{"name":"","type":"string"}],"stateMutability":"view","type":"function"},
+{"constant":true,"inputs":[],"name":"decimals","outputs":[{"name":"","type":"uint8"}],"stateMutability":"view","type":"function"},
+{"constant":true,"inputs":[],"name":"totalSupply","outputs":[{"name":"","type":"uint256"}],"stateMutability":"view","type":"function"},
+{"constant":true,"inputs":[{"name":"owner","type":"address"}],"name":"balanceOf","outputs":[{"name":"","type":"uint256"}],"stateMutability":"view","type":"function"},
+{"constant":true,"inputs":[{"name":"owner","type":"address"},{"name":"spender","type":"address"}],"name":"allowance","outputs":[{"name":"","type":"uint256"}],"stateMutability":"view","type":"function"},
+{"constant":false,"inputs":[{"name":"to","type":"address"},{"name":"value","type":"uint256"}],"name":"transfer","outputs":[{"name":"","type":"bool"}],"stateMutability":"nonpayable","type":"function"},
+{"constant":false,"inputs":[{"name":"spender","type":"address"},{"name":"value","type":"uint256"}],"name":"approve","outputs":[{"name":"","type":"bool"}],"stateMutability":"nonpayable","type":"function"},
+{"constant":false,"inputs":[{"name":"from","type":"address"},{"name":"to","type":"address"},{"name":"value","type":"uint256"}],"name":"transferFrom","outputs":[{"name":"","type":"bool"}],"stateMutability":"nonpayable","type":"function"},
+{"anonymous":false,"inputs":[{"indexed":true,"name":"from","type":"address"},{"indexed":true,"name":"to","type":"address"},{"indexed":false,"name":"value","type":"uint256"}],"name":"Transfer","type":"event"},
+{"anonymous":false,"inputs":[{"indexed":true,"name":"owner","type":"address"},{"indexed":true,"name":"spender","type":"address"},{"indexed":false,"name":"value","type":"uint256"}],"name":"Approval","type":"event"}
+]`
+
+var erc20ABI = mustParseABI(ERC20ABI)
+
+// ZeroAddress 只读调用默认使用的 from 地址
+const ZeroAddress = "0x0000000000000000000000000000000000000000"
+
+func mustParseABI(s string) abi.ABI {
+	a, err := abi.JSON(strings.NewReader(s))
+	if err != nil {
+		panic(err)
+	}
+	return a
+}
+
+// ERC20 绑定到代币合约地址的 ERC-20 客户端
+// 金额参数为 *big.Int(代币最小单位)或十进制字符串(按 Decimals 换算，如 "1.5")
+type ERC20 struct {
+	Client  *Client
+	Address string
+	// CallFrom 只读调用使用的 from 地址，为空时使用零地址
+	CallFrom string
+
+	mu       sync.Mutex
+	decimals *uint8
+}
+
+func NewERC20(client *Client, address string) *ERC20 {
+	return &ERC20{Client: client, Address: address}
+}
+
+// ERC20Transfer 解码后的 Transfer 事件
+type ERC20Transfer struct {
+	Token string
+	From  string
+	To    string
+	Value *big.Int
+	Event Event
+}
+
+// ERC20Approval 解码后的 Approval 事件
+type ERC20Approval struct {
+	Token   string
+	Owner   string
+	Spender string
+	Value   *big.Int
+	Event   Event
+}
+
+var (
+	ERC20TransferTopic = erc20ABI.Events["Transfer"].ID.Hex()
+	ERC20ApprovalTopic = erc20ABI.Events["Approval"].ID.Hex()
+)
+
+func (t *ERC20) Name() (string, error) {
+	var out string
+	err := t.call(&out, "name")
+	return out, err
+}
+
+func (t *ERC20) Symbol() (string, error) {
+	var out string
+	err := t.call(&out, "symbol")
+	return out, err
+}
+
+// Decimals 代币精度，首次调用后缓存
+func (t *ERC20) Decimals() (uint8, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.decimals != nil {
+		return *t.decimals, nil
+	}
+	var out uint8
+	if err := t.call(&out, "decimals"); err != nil {
+		return 0, err
+	}
+	t.decimals = &out
+	return out, nil
+}
+
+func (t *ERC20) TotalSupply() (*big.Int, error) {
+	out := new(*big.Int)
+	err := t.call(out, "totalSupply")
+	return *out, err
+}
+
+func (t *ERC20) BalanceOf(owner string) (*big.Int, error) {
+	ownerAddr, err := parseAddress(owner)
+	if err != nil {
+		return nil, err
+	}
+	out := new(*big.Int)
+	err = t.call(out, "balanceOf", ownerAddr)
+	return *out, err
+}
+
+func (t *ERC20) Allowance(owner, spender string) (*big.Int, error) {
+	addrs, err := parseAddresses(owner, spender)
+	if err != nil {
+		return nil, err
+	}
+	out := new(*big.Int)
+	err = t.call(out, "allowance", addrs[0], addrs[1])
+	return *out, err
+}
+
+// Transfer 从 from 向 to 转账 amount
+func (t *ERC20) Transfer(from, to string, amount interface{}) (TransactResponse, error) {
+	toAddr, err := parseAddress(to)
+	if err != nil {
+		return TransactResponse{}, err
+	}
+	value, err := t.ParseAmount(amount)
+	if err != nil {
+		return TransactResponse{}, err
+	}
+	return t.send(from, "transfer", toAddr, value)
+}
+
+// Approve 授权 spender 使用 owner 的 amount 代币
+func (t *ERC20) Approve(owner, spender string, amount interface{}) (TransactResponse, error) {
+	spenderAddr, err := parseAddress(spender)
+	if err != nil {
+		return TransactResponse{}, err
+	}
+	value, err := t.ParseAmount(amount)
+	if err != nil {
+		return TransactResponse{}, err
+	}
+	return t.send(owner, "approve", spenderAddr, value)
+}
+
+// TransferFrom 由 spender 发起，使用授权从 from 向 to 转账 amount
+func (t *ERC20) TransferFrom(spender, from, to string, amount interface{}) (TransactResponse, error) {
+	addrs, err := parseAddresses(from, to)
+	if err != nil {
+		return TransactResponse{}, err
+	}
+	value, err := t.ParseAmount(amount)
+	if err != nil {
+		return TransactResponse{}, err
+	}
+	return t.send(spender, "transferFrom", addrs[0], addrs[1], value)
+}
+
+// ParseAmount 将 *big.Int 或按 Decimals 换算的十进制字符串转换为代币最小单位
+func (t *ERC20) ParseAmount(amount interface{}) (*big.Int, error) {
+	switch a := amount.(type) {
+	case *big.Int:
+		if a == nil || a.Sign() < 0 {
+			return nil, fmt.Errorf("invalid token amount %v", a)
+		}
+		return a, nil
+	case string:
+		decimals, err := t.Decimals()
+		if err != nil {
+			return nil, err
+		}
+		return ParseUnits(a, Unit(decimals))
+	}
+	return nil, fmt.Errorf("unsupported amount type %T", amount)
+}
+
+// FormatAmount 将代币最小单位按 Decimals 格式化为十进制字符串
+func (t *ERC20) FormatAmount(value *big.Int) (string, error) {
+	decimals, err := t.Decimals()
+	if err != nil {
+		return "", err
+	}
+	return FormatUnits(value, Unit(decimals)), nil
+}
+
+func (t *ERC20) call(out interface{}, method string, args ...interface{}) error {
+	return contractCall(t.Client, t.CallFrom, t.Address, erc20ABI, out, method, args...)
+}
+
+func (t *ERC20) send(from, method string, args ...interface{}) (TransactResponse, error) {
+	return contractSend(t.Client, from, t.Address, erc20ABI, method, args...)
+}
+
+// DecodeERC20Events 解码 EventQuery 返回的 Transfer 与 Approval 事件，忽略其他事件(包括 ERC-721 的 Transfer)
+func DecodeERC20Events(events []Event) ([]ERC20Transfer, []ERC20Approval, error) {
+	var transfers []ERC20Transfer
+	var approvals []ERC20Approval
+	for _, e := range events {
+		l := e.Log()
+		// ERC-721 的 Transfer/Approval 有相同的 topic0，但 tokenId 为 indexed，共4个 topic
+		if len(l.Topics) != 3 {
+			continue
+		}
+		d, err := DecodeLog(erc20ABI, l)
+		if err == ErrUnknownEvent {
+			continue
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+		switch d.Event {
+		case "Transfer":
+			transfers = append(transfers, ERC20Transfer{
+				Token: e.Address,
+				From:  d.Args["from"].(common.Address).Hex(),
+				To:    d.Args["to"].(common.Address).Hex(),
+				Value: d.Args["value"].(*big.Int),
+				Event: e,
+			})
+		case "Approval":
+			approvals = append(approvals, ERC20Approval{
+				Token:   e.Address,
+				Owner:   d.Args["owner"].(common.Address).Hex(),
+				Spender: d.Args["spender"].(common.Address).Hex(),
+				Value:   d.Args["value"].(*big.Int),
+				Event:   e,
+			})
+		}
+	}
+	return transfers, approvals, nil
+}
+
+// Transfers 查询区块范围内该代币的 Transfer 事件
+func (t *ERC20) Transfers(fromBlock, toBlock int64) ([]ERC20Transfer, error) {
+	res, err := t.Client.EventQuery(QueryRequest{
+		FromBlock: fromBlock,
+		ToBlock:   toBlock,
+		Addresses: []string{t.Address},
+		Topics:    [][]string{{ERC20TransferTopic}},
+	})
+	if err != nil {
+		return nil, err
+	}
+	transfers, _, err := DecodeERC20Events(res.Data)
+	return transfers, err
+}
+
+// parseAddress 按 eth_addr 规则校验地址(包括 EIP-55 checksum)
+func parseAddress(s string) (common.Address, error) {
+	if msg := checkAddress(s); msg != "" {
+		return common.Address{}, errors.New(msg)
+	}
+	return common.HexToAddress(s), nil
+}
+
+func parseAddresses(ss ...string) ([]common.Address, error) {
+	addrs := make([]common.Address, len(ss))
+	for i, s := range ss {
+		a, err := parseAddress(s)
+		if err != nil {
+			return nil, err
+		}
+		addrs[i] = a
+	}
+	return addrs, nil
+}
+
+// contractCall 通过 tx/call 调用合约只读方法并按 ABI 解码到 out
+func contractCall(client *Client, from, to string, contractABI abi.ABI, out interface{}, method string, args ...interface{}) error {
+	data, err := contractABI.Pack(method, args...)
+	if err != nil {
+		return err
+	}
+	if from == "" {
+		from = ZeroAddress
+	}
+	return client.Call(CallRequest{
+		From:   from,
+		To:     to,
+		Data:   hexutil.Encode(data),
+		Method: method,
+	}, contractABI, out)
+}
+
+// contractSend 通过 SendTx 发送调用合约方法的交易
+func contractSend(client *Client, from, to string, contractABI abi.ABI, method string, args ...interface{}) (TransactResponse, error) {
+	data, err := contractABI.Pack(method, args...)
+	if err != nil {
+		return TransactResponse{}, err
+	}
+	return client.SendTx(TransactRequest{
+		From: from,
+		To:   to,
+		Data: hexutil.Encode(data),
+	})
+}
@@ -0,0 +1,88 @@
+package tokenup_sdk_test
+
+import (
+	"github.com/cblk/tokenup-sdk"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"math/big"
+	"strings"
+	"testing"
+)
+
+const tokenAddress = "0x1111111111111111111111111111111111111111"
+
+func TestERC20(t *testing.T) {
+	erc20, err := abi.JSON(strings.NewReader(tokenup_sdk.ERC20ABI))
+	if err != nil {
+		t.Fatal(err)
+	}
+	word := func(v int64) string { return hexutil.Encode(common.LeftPadBytes(big.NewInt(v).Bytes(), 32)) }
+	selector := func(name string) string { return hexutil.Encode(erc20.Methods[name].ID) }
+	symbol, _ := erc20.Methods["symbol"].Outputs.Pack("USDT")
+	results := map[string]string{
+		selector("decimals"):  word(6),
+		selector("balanceOf"): word(2500000),
+		selector("symbol"):    hexutil.Encode(symbol),
+	}
+	node, client := newFakeNode(t)
+	node.acceptTx()
+	node.replyCalls(results)
+
+	token := tokenup_sdk.NewERC20(client, tokenAddress)
+	if s, err := token.Symbol(); err != nil || s != "USDT" {
+		t.Fatalf("symbol %q %v", s, err)
+	}
+	balance, err := token.BalanceOf(node.address())
+	if err != nil || balance.Int64() != 2500000 {
+		t.Fatalf("balance %v %v", balance, err)
+	}
+	if s, err := token.FormatAmount(balance); err != nil || s != "2.5" {
+		t.Fatalf("formatted %q %v", s, err)
+	}
+	if _, err := token.Decimals(); err != nil {
+		t.Fatal(err)
+	}
+	if n := node.count("/v1/tx/call"); n != 3 {
+		t.Fatalf("decimals not cached: %d calls", n)
+	}
+
+	to := "0xdF0F1b2Fa2992247ffC68790B20a3Df1E7514B68"
+	if _, err := token.Transfer(node.address(), to, "1.25"); err != nil {
+		t.Fatal(err)
+	}
+	want, _ := erc20.Pack("transfer", common.HexToAddress(to), big.NewInt(1250000))
+	sent := node.sent()
+	if len(sent) != 1 || sent[0].To != tokenAddress || sent[0].Data != hexutil.Encode(want) {
+		t.Fatalf("sent %+v", sent)
+	}
+	if _, err := token.Transfer(node.address(), "0xdf0F1b2Fa2992247ffC68790B20a3Df1E7514B68", "1"); err == nil {
+		t.Fatal("bad checksum recipient accepted")
+	}
+	if _, err := token.Approve(node.address(), to, "0.0000001"); err == nil {
+		t.Fatal("amount beyond token decimals accepted")
+	}
+}
+
+func TestDecodeERC20Events(t *testing.T) {
+	from := common.HexToAddress("0xdF0F1b2Fa2992247ffC68790B20a3Df1E7514B68")
+	to := common.HexToAddress("0x0000000000000000000000000000000000000001")
+	topic := func(a common.Address) string { return common.BytesToHash(a.Bytes()).Hex() }
+	value := hexutil.Encode(common.LeftPadBytes(big.NewInt(42).Bytes(), 32))
+	events := []tokenup_sdk.Event{
+		{Address: tokenAddress, Data: value, Topics: `["` + tokenup_sdk.ERC20TransferTopic + `","` + topic(from) + `","` + topic(to) + `"]`},
+		{Address: tokenAddress, Data: value, Topics: tokenup_sdk.ERC20ApprovalTopic + "," + topic(from) + "," + topic(to)},
+		// ERC-721 Transfer: tokenId 为 indexed
+		{Address: tokenAddress, Topics: tokenup_sdk.ERC20TransferTopic + " " + topic(from) + " " + topic(to) + " " + common.BigToHash(big.NewInt(1)).Hex()},
+	}
+	transfers, approvals, err := tokenup_sdk.DecodeERC20Events(events)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(transfers) != 1 || transfers[0].From != from.Hex() || transfers[0].To != to.Hex() || transfers[0].Value.Int64() != 42 {
+		t.Fatalf("transfers %+v", transfers)
+	}
+	if len(approvals) != 1 || approvals[0].Owner != from.Hex() || approvals[0].Spender != to.Hex() {
+		t.Fatalf("approvals %+v", approvals)
+	}
+}
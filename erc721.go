@@ -0,0 +1,136 @@
+package tokenup_sdk
+
+import (
+	"github.com/ethereum/go-ethereum/common"
+	"math/big"
+)
+
+// ERC721ABI 标准 ERC-721 接口(含 Metadata 扩展)
+const ERC721ABI = `[
+{"inputs":[{"name":"owner","type":"address"}],"name":"balanceOf","outputs":[{"name":"","type":"uint256"}],"stateMutability":"view","type":"function"},
+{"inputs":[{"name":"tokenId","type":"uint256"}],"name":"ownerOf","outputs":[{"name":"","type":"address"}],"stateMutability":"view","type":"function"},
+{"inputs":[{"name":"tokenId","type":"uint256"}],"name":"tokenURI","outputs":[{"name":"","type":"string"}],"stateMutability":"view","type":"function"},
+{"inputs":[{"name":"owner","type":"address"},{"name":"operator","type":"address"}],"name":"isApprovedForAll","outputs":[{"name":"","type":"bool"}],"stateMutability":"view","type":"function"},
+{"inputs":[{"name":"from","type":"address"},{"name":"to","type":"address"},{"name":"tokenId","type":"uint256"},{"name":"data","type":"bytes"}],"name":"safeTransferFrom","outputs":[],"stateMutability":"nonpayable","type":"function"},
+{"inputs":[{"name":"operator","type":"address"},{"name":"approved","type":"bool"}],"name":"setApprovalForAll","outputs":[],"stateMutability":"nonpayable","type":"function"},
+{"anonymous":false,"inputs":[{"indexed":true,"name":"from","type":"address"},{"indexed":true,"name":"to","type":"address"},{"indexed":true,"name":"tokenId","type":"uint256"}],"name":"Transfer","type":"event"},
+{"anonymous":false,"inputs":[{"indexed":true,"name":"owner","type":"address"},{"indexed":true,"name":"operator","type":"address"},{"indexed":false,"name":"approved","type":"bool"}],"name":"ApprovalForAll","type":"event"}
+]`
+
+var erc721ABI = mustParseABI(ERC721ABI)
+
+// ERC721TransferTopic 与 ERC20TransferTopic 相同，区别在于 ERC-721 的 tokenId 为 indexed
+var ERC721TransferTopic = erc721ABI.Events["Transfer"].ID.Hex()
+
+// ERC721 绑定到合约地址的 ERC-721 客户端
+type ERC721 struct {
+	Client  *Client
+	Address string
+	// CallFrom 只读调用使用的 from 地址，为空时使用零地址
+	CallFrom string
+}
+
+func NewERC721(client *Client, address string) *ERC721 {
+	return &ERC721{Client: client, Address: address}
+}
+
+// ERC721Transfer 解码后的 Transfer 事件，铸造时 From 为零地址，销毁时 To 为零地址
+type ERC721Transfer struct {
+	Token   string
+	From    string
+	To      string
+	TokenId *big.Int
+	Event   Event
+}
+
+func (t *ERC721) BalanceOf(owner string) (*big.Int, error) {
+	ownerAddr, err := parseAddress(owner)
+	if err != nil {
+		return nil, err
+	}
+	out := new(*big.Int)
+	err = t.call(out, "balanceOf", ownerAddr)
+	return *out, err
+}
+
+func (t *ERC721) OwnerOf(tokenId *big.Int) (string, error) {
+	var out common.Address
+	if err := t.call(&out, "ownerOf", tokenId); err != nil {
+		return "", err
+	}
+	return out.Hex(), nil
+}
+
+func (t *ERC721) TokenURI(tokenId *big.Int) (string, error) {
+	var out string
+	err := t.call(&out, "tokenURI", tokenId)
+	return out, err
+}
+
+func (t *ERC721) IsApprovedForAll(owner, operator string) (bool, error) {
+	addrs, err := parseAddresses(owner, operator)
+	if err != nil {
+		return false, err
+	}
+	var out bool
+	err = t.call(&out, "isApprovedForAll", addrs[0], addrs[1])
+	return out, err
+}
+
+// SafeTransferFrom 由 sender(from 本身或已授权的 operator)发起，将 tokenId 从 from 转给 to，data 可以为 nil
+func (t *ERC721) SafeTransferFrom(sender, from, to string, tokenId *big.Int, data []byte) (TransactResponse, error) {
+	addrs, err := parseAddresses(from, to)
+	if err != nil {
+		return TransactResponse{}, err
+	}
+	if data == nil {
+		data = []byte{}
+	}
+	return t.send(sender, "safeTransferFrom", addrs[0], addrs[1], tokenId, data)
+}
+
+// SetApprovalForAll 授权或取消 operator 管理 owner 的全部 token
+func (t *ERC721) SetApprovalForAll(owner, operator string, approved bool) (TransactResponse, error) {
+	operatorAddr, err := parseAddress(operator)
+	if err != nil {
+		return TransactResponse{}, err
+	}
+	return t.send(owner, "setApprovalForAll", operatorAddr, approved)
+}
+
+func (t *ERC721) call(out interface{}, method string, args ...interface{}) error {
+	return contractCall(t.Client, t.CallFrom, t.Address, erc721ABI, out, method, args...)
+}
+
+func (t *ERC721) send(from, method string, args ...interface{}) (TransactResponse, error) {
+	return contractSend(t.Client, from, t.Address, erc721ABI, method, args...)
+}
+
+// DecodeERC721Transfers 解码 EventQuery 返回的 ERC-721 Transfer 事件，忽略其他事件(包括 ERC-20 的 Transfer)
+func DecodeERC721Transfers(events []Event) ([]ERC721Transfer, error) {
+	var transfers []ERC721Transfer
+	for _, e := range events {
+		l := e.Log()
+		if len(l.Topics) != 4 {
+			continue
+		}
+		d, err := DecodeLog(erc721ABI, l)
+		if err == ErrUnknownEvent {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		if d.Event != "Transfer" {
+			continue
+		}
+		transfers = append(transfers, ERC721Transfer{
+			Token:   e.Address,
+			From:    d.Args["from"].(common.Address).Hex(),
+			To:      d.Args["to"].(common.Address).Hex(),
+			TokenId: d.Args["tokenId"].(*big.Int),
+			Event:   e,
+		})
+	}
+	return transfers, nil
+}
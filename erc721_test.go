@@ -0,0 +1,65 @@
+package tokenup_sdk_test
+
+import (
+	"github.com/cblk/tokenup-sdk"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"math/big"
+	"strings"
+	"testing"
+)
+
+func TestERC721(t *testing.T) {
+	erc721, err := abi.JSON(strings.NewReader(tokenup_sdk.ERC721ABI))
+	if err != nil {
+		t.Fatal(err)
+	}
+	owner := common.HexToAddress("0x2222222222222222222222222222222222222222")
+	ownerOf, _ := erc721.Methods["ownerOf"].Outputs.Pack(owner)
+	uri, _ := erc721.Methods["tokenURI"].Outputs.Pack("ipfs://token/1")
+	results := map[string]string{
+		hexutil.Encode(erc721.Methods["ownerOf"].ID):  hexutil.Encode(ownerOf),
+		hexutil.Encode(erc721.Methods["tokenURI"].ID): hexutil.Encode(uri),
+	}
+	node, client := newFakeNode(t)
+	node.acceptTx()
+	node.replyCalls(results)
+
+	nft := tokenup_sdk.NewERC721(client, tokenAddress)
+	if got, err := nft.OwnerOf(big.NewInt(1)); err != nil || got != owner.Hex() {
+		t.Fatalf("owner %s %v", got, err)
+	}
+	if got, err := nft.TokenURI(big.NewInt(1)); err != nil || got != "ipfs://token/1" {
+		t.Fatalf("uri %s %v", got, err)
+	}
+
+	to := "0xdF0F1b2Fa2992247ffC68790B20a3Df1E7514B68"
+	if _, err := nft.SafeTransferFrom(node.address(), node.address(), to, big.NewInt(1), nil); err != nil {
+		t.Fatal(err)
+	}
+	want, _ := erc721.Pack("safeTransferFrom", common.HexToAddress(node.address()), common.HexToAddress(to), big.NewInt(1), []byte{})
+	if sent := node.sent(); len(sent) != 1 || sent[0].Data != hexutil.Encode(want) {
+		t.Fatalf("sent %+v", sent)
+	}
+	if _, err := nft.SetApprovalForAll(node.address(), to, true); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestDecodeERC721Transfers(t *testing.T) {
+	to := common.HexToAddress("0xdF0F1b2Fa2992247ffC68790B20a3Df1E7514B68")
+	zero := common.Hash{}.Hex()
+	events := []tokenup_sdk.Event{
+		{Address: tokenAddress, Topics: strings.Join([]string{tokenup_sdk.ERC721TransferTopic, zero, common.BytesToHash(to.Bytes()).Hex(), common.BigToHash(big.NewInt(7)).Hex()}, ",")},
+		// ERC-20 Transfer: value 不是 indexed
+		{Address: tokenAddress, Data: common.BigToHash(big.NewInt(7)).Hex(), Topics: strings.Join([]string{tokenup_sdk.ERC20TransferTopic, zero, zero}, ",")},
+	}
+	transfers, err := tokenup_sdk.DecodeERC721Transfers(events)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(transfers) != 1 || transfers[0].To != to.Hex() || transfers[0].TokenId.Int64() != 7 || transfers[0].From != common.HexToAddress(zero).Hex() {
+		t.Fatalf("transfers %+v", transfers)
+	}
+}
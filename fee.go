@@ -0,0 +1,72 @@
+package tokenup_sdk
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+var gwei = big.NewInt(1000000000)
+
+// FeeCapError 交易费用(GasLimit * GasPrice)或发送金额超过上限，在请求签名前返回
+// 未超限的一项对应的 Max 字段为 nil
+type FeeCapError struct {
+	GasLimit uint64
+	GasPrice *big.Int
+	Fee      *big.Int
+	MaxFee   *big.Int
+	Value    *big.Int
+	MaxValue *big.Int
+}
+
+func (e *FeeCapError) Error() string {
+	var msgs []string
+	if e.MaxFee != nil {
+		msgs = append(msgs, fmt.Sprintf("fee gas_limit %d * gas_price %v wei = %v wei exceeds max fee %v wei",
+			e.GasLimit, e.GasPrice, e.Fee, e.MaxFee))
+	}
+	if e.MaxValue != nil {
+		msgs = append(msgs, fmt.Sprintf("value %v wei exceeds max value %v wei", e.Value, e.MaxValue))
+	}
+	return "fee cap exceeded: " + strings.Join(msgs, "; ")
+}
+
+// maxFee 返回 Client 的单笔交易费用上限(Wei)，未配置 MaxFee 时为 FeeLimit(Gwei)
+func (client *Client) maxFee() *big.Int {
+	if client.MaxFee != nil {
+		return client.MaxFee
+	}
+	return new(big.Int).Mul(big.NewInt(client.FeeLimit), gwei)
+}
+
+// checkFeeCap 检查已补全 gas 的交易是否超过 Client 与请求上的上限，两者都设置时取较小值
+func (client *Client) checkFeeCap(req TransactRequest) error {
+	tx, err := req.transaction()
+	if err != nil {
+		return err
+	}
+	fee := new(big.Int).Mul(new(big.Int).SetUint64(tx.Gas()), tx.GasPrice())
+	maxFee := minCap(client.maxFee(), req.MaxFee)
+	maxValue := minCap(client.MaxValue, req.MaxValue)
+	e := &FeeCapError{GasLimit: tx.Gas(), GasPrice: tx.GasPrice(), Fee: fee, Value: tx.Value()}
+	if maxFee != nil && fee.Cmp(maxFee) > 0 {
+		e.MaxFee = maxFee
+	}
+	if maxValue != nil && tx.Value().Cmp(maxValue) > 0 {
+		e.MaxValue = maxValue
+	}
+	if e.MaxFee == nil && e.MaxValue == nil {
+		return nil
+	}
+	return e
+}
+
+func minCap(a, b *big.Int) *big.Int {
+	if a == nil {
+		return b
+	}
+	if b == nil || a.Cmp(b) <= 0 {
+		return a
+	}
+	return b
+}
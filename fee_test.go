@@ -0,0 +1,44 @@
+package tokenup_sdk_test
+
+import (
+	"github.com/cblk/tokenup-sdk"
+	"math/big"
+	"strings"
+	"testing"
+)
+
+func TestSendTx_FeeCap(t *testing.T) {
+	node, client := newFakeNode(t)
+	node.acceptTx()
+	to := "0xdF0F1b2Fa2992247ffC68790B20a3Df1E7514B68"
+
+	// 21000 * 10 Gwei = 210000 Gwei，默认上限为 FeeLimit 0.05 Ether
+	client.MaxValue = big.NewInt(1000)
+	_, err := client.SendTx(tokenup_sdk.TransactRequest{From: node.address(), To: to, Value: "0x3e9"})
+	capErr, ok := err.(*tokenup_sdk.FeeCapError)
+	if !ok || capErr.MaxFee != nil || capErr.MaxValue.Int64() != 1000 || capErr.Value.Int64() != 1001 {
+		t.Fatalf("err %v", err)
+	}
+
+	_, err = client.SendTx(tokenup_sdk.TransactRequest{From: node.address(), To: to, MaxFee: big.NewInt(210000000000000 - 1)})
+	capErr, ok = err.(*tokenup_sdk.FeeCapError)
+	if !ok || capErr.MaxValue != nil || capErr.Fee.String() != "210000000000000" {
+		t.Fatalf("err %v", err)
+	}
+	if !strings.Contains(err.Error(), "gas_limit 21000 * gas_price 10000000000 wei = 210000000000000 wei") {
+		t.Fatalf("message %q", err.Error())
+	}
+	if len(node.signed()) != 0 || node.count("/v1/tx/transact") != 0 {
+		t.Fatal("over-limit transaction was signed")
+	}
+
+	// 请求上的上限不能放宽 Client 的上限
+	client.MaxFee = big.NewInt(1)
+	if _, err := client.SendTx(tokenup_sdk.TransactRequest{From: node.address(), To: to, MaxFee: big.NewInt(1e18)}); err == nil {
+		t.Fatal("request cap overrode client cap")
+	}
+	client.MaxFee = nil
+	if _, err := client.SendTx(tokenup_sdk.TransactRequest{From: node.address(), To: to, Value: "0x3e8"}); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,183 @@
+package tokenup_sdk
+
+import (
+	"errors"
+	"fmt"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"math"
+	"math/big"
+)
+
+// GasQuote 计算 gas price 的输入，Estimate 为节点建议的 gas price，Min/Max 为 Client 配置的上下限，单位均为 Wei
+type GasQuote struct {
+	Estimate *big.Int
+	Min      *big.Int
+	Max      *big.Int
+}
+
+// GasStrategy 决定交易使用的 gas price，可以在 NodeConfig 与 TransactRequest 上分别设置，后者优先
+type GasStrategy interface {
+	GasPrice(q GasQuote) (*big.Int, error)
+}
+
+// NodeEstimate 直接使用节点建议的 gas price
+type NodeEstimate struct{}
+
+func (NodeEstimate) GasPrice(q GasQuote) (*big.Int, error) {
+	if q.Estimate == nil {
+		return nil, errors.New("node returned no gas price estimate")
+	}
+	return new(big.Int).Set(q.Estimate), nil
+}
+
+// MultiplierStrategy 节点建议值乘以 Multiplier 后限制在 [Min, Max] 内，Min/Max 为 nil 时使用 Client 配置
+type MultiplierStrategy struct {
+	Multiplier float64
+	Min        *big.Int
+	Max        *big.Int
+}
+
+func (s MultiplierStrategy) GasPrice(q GasQuote) (*big.Int, error) {
+	if q.Estimate == nil {
+		return nil, errors.New("node returned no gas price estimate")
+	}
+	if s.Multiplier <= 0 {
+		return nil, fmt.Errorf("invalid gas price multiplier %v", s.Multiplier)
+	}
+	price := mulBig(q.Estimate, s.Multiplier)
+	min, max := q.Min, q.Max
+	if s.Min != nil {
+		min = s.Min
+	}
+	if s.Max != nil {
+		max = s.Max
+	}
+	return clampBig(price, min, max), nil
+}
+
+// FixedGasPrice 固定 gas price，忽略节点建议值
+type FixedGasPrice struct {
+	Price *big.Int
+}
+
+func (s FixedGasPrice) GasPrice(q GasQuote) (*big.Int, error) {
+	if s.Price == nil || s.Price.Sign() <= 0 {
+		return nil, errors.New("fixed gas price must be positive")
+	}
+	return new(big.Int).Set(s.Price), nil
+}
+
+// Urgency 按紧急程度调整节点建议值，结果限制在 Client 配置的上下限内
+type Urgency int
+
+const (
+	GasSlow Urgency = iota
+	GasStandard
+	GasFast
+)
+
+var urgencyMultipliers = map[Urgency]float64{
+	GasSlow:     0.8,
+	GasStandard: 1.0,
+	GasFast:     1.3,
+}
+
+func (u Urgency) GasPrice(q GasQuote) (*big.Int, error) {
+	m, ok := urgencyMultipliers[u]
+	if !ok {
+		return nil, fmt.Errorf("unknown gas urgency %d", u)
+	}
+	return MultiplierStrategy{Multiplier: m}.GasPrice(q)
+}
+
+func (u Urgency) String() string {
+	switch u {
+	case GasSlow:
+		return "slow"
+	case GasStandard:
+		return "standard"
+	case GasFast:
+		return "fast"
+	}
+	return fmt.Sprintf("Urgency(%d)", int(u))
+}
+
+// gasPrice 按 strategy(为 nil 时使用 Client 配置，仍为 nil 时使用节点建议值)计算16进制 gas price
+func (client *Client) gasPrice(strategy GasStrategy, estimate string) (string, error) {
+	if strategy == nil {
+		strategy = client.GasStrategy
+	}
+	if strategy == nil {
+		return estimate, nil
+	}
+	q := GasQuote{
+		Min: big.NewInt(client.GasPriceMin),
+		Max: big.NewInt(client.GasPriceMax),
+	}
+	if estimate != "" {
+		est, err := hexutil.DecodeBig(estimate)
+		if err != nil {
+			return "", fmt.Errorf("invalid estimated gas price %q: %v", estimate, err)
+		}
+		q.Estimate = est
+	}
+	price, err := strategy.GasPrice(q)
+	if err != nil {
+		return "", err
+	}
+	return hexutil.EncodeBig(price), nil
+}
+
+// GasLimitMargin 在节点估算的 gas 用量上增加余量，结果为 Estimate * Multiplier + Buffer，
+// Cap 不为0时结果不超过 Cap；节点估算值已经超过 Cap 时发送交易返回错误，而不是使用超过 Cap 的 gas limit
+type GasLimitMargin struct {
+	Multiplier float64 // 为0时视为1
+	Buffer     uint64
+	Cap        uint64
+}
+
+// Apply 返回加上余量后的 gas limit，结果不会低于 estimate，estimate 超过 Cap 时原样返回
+func (m GasLimitMargin) Apply(estimate uint64) uint64 {
+	limit := estimate
+	if m.Multiplier > 0 {
+		limit = uint64(math.Ceil(float64(estimate) * m.Multiplier))
+	}
+	limit += m.Buffer
+	if m.Cap > 0 && limit > m.Cap {
+		limit = m.Cap
+	}
+	if limit < estimate {
+		limit = estimate
+	}
+	return limit
+}
+
+// gasLimit 对节点估算的16进制 gas 用量应用 NodeConfig.GasLimitMargin
+func (client *Client) gasLimit(estimate string) (string, error) {
+	gas, err := hexutil.DecodeUint64(estimate)
+	if err != nil {
+		return "", fmt.Errorf("invalid estimated gas %q: %v", estimate, err)
+	}
+	if limitCap := client.GasLimitMargin.Cap; limitCap > 0 && gas > limitCap {
+		return "", fmt.Errorf("estimated gas %d exceeds GasLimitMargin.Cap %d", gas, limitCap)
+	}
+	return hexutil.EncodeUint64(client.GasLimitMargin.Apply(gas)), nil
+}
+
+// mulBig 将 m 换算为万分比后计算 x * m，结果向上取整，避免 0.7 * 10 Gwei 因浮点误差得到 6999999999 Wei
+func mulBig(x *big.Int, m float64) *big.Int {
+	bp := big.NewInt(int64(math.Round(m * 10000)))
+	r := new(big.Int).Mul(x, bp)
+	r.Add(r, big.NewInt(9999))
+	return r.Div(r, big.NewInt(10000))
+}
+
+func clampBig(x, min, max *big.Int) *big.Int {
+	if min != nil && min.Sign() > 0 && x.Cmp(min) < 0 {
+		return new(big.Int).Set(min)
+	}
+	if max != nil && max.Sign() > 0 && x.Cmp(max) > 0 {
+		return new(big.Int).Set(max)
+	}
+	return x
+}
@@ -0,0 +1,93 @@
+package tokenup_sdk_test
+
+import (
+	"github.com/cblk/tokenup-sdk"
+	"math/big"
+	"strings"
+	"testing"
+)
+
+func TestGasStrategies(t *testing.T) {
+	gwei := func(n int64) *big.Int { return new(big.Int).Mul(big.NewInt(n), big.NewInt(1000000000)) }
+	q := tokenup_sdk.GasQuote{Estimate: gwei(10), Min: gwei(2), Max: gwei(30)}
+	cases := []struct {
+		name     string
+		strategy tokenup_sdk.GasStrategy
+		want     *big.Int
+	}{
+		{"node estimate", tokenup_sdk.NodeEstimate{}, gwei(10)},
+		{"multiplier", tokenup_sdk.MultiplierStrategy{Multiplier: 1.5}, gwei(15)},
+		{"multiplier without float error", tokenup_sdk.MultiplierStrategy{Multiplier: 0.7}, gwei(7)},
+		{"multiplier clamped to client max", tokenup_sdk.MultiplierStrategy{Multiplier: 5}, gwei(30)},
+		{"multiplier clamped to own min", tokenup_sdk.MultiplierStrategy{Multiplier: 0.1, Min: gwei(3)}, gwei(3)},
+		{"fixed", tokenup_sdk.FixedGasPrice{Price: gwei(7)}, gwei(7)},
+		{"slow", tokenup_sdk.GasSlow, gwei(8)},
+		{"standard", tokenup_sdk.GasStandard, gwei(10)},
+		{"fast", tokenup_sdk.GasFast, gwei(13)},
+	}
+	for _, c := range cases {
+		got, err := c.strategy.GasPrice(q)
+		if err != nil {
+			t.Errorf("%s: %v", c.name, err)
+			continue
+		}
+		if got.Cmp(c.want) != 0 {
+			t.Errorf("%s: got %v, want %v", c.name, got, c.want)
+		}
+	}
+	if got, err := (tokenup_sdk.MultiplierStrategy{Multiplier: 1.5}).GasPrice(tokenup_sdk.GasQuote{Estimate: big.NewInt(3)}); err != nil || got.Int64() != 5 {
+		t.Errorf("multiplier should round up: got %v %v", got, err)
+	}
+	if _, err := (tokenup_sdk.FixedGasPrice{}).GasPrice(q); err == nil {
+		t.Error("zero fixed gas price accepted")
+	}
+}
+
+func TestGasLimitMargin(t *testing.T) {
+	cases := []struct {
+		margin tokenup_sdk.GasLimitMargin
+		want   uint64
+	}{
+		{tokenup_sdk.GasLimitMargin{}, 50000},
+		{tokenup_sdk.GasLimitMargin{Multiplier: 1.2}, 60000},
+		{tokenup_sdk.GasLimitMargin{Buffer: 5000}, 55000},
+		{tokenup_sdk.GasLimitMargin{Multiplier: 1.5, Buffer: 5000, Cap: 70000}, 70000},
+		{tokenup_sdk.GasLimitMargin{Multiplier: 2, Cap: 40000}, 50000},
+	}
+	for _, c := range cases {
+		if got := c.margin.Apply(50000); got != c.want {
+			t.Errorf("%+v: got %d, want %d", c.margin, got, c.want)
+		}
+	}
+}
+
+func TestSendTx_GasLimit(t *testing.T) {
+	node, client := newFakeNode(t)
+	node.acceptTx()
+	to := "0xdF0F1b2Fa2992247ffC68790B20a3Df1E7514B68"
+	client.GasLimitMargin = tokenup_sdk.GasLimitMargin{Multiplier: 1.5}
+	if _, err := client.SendTx(tokenup_sdk.TransactRequest{From: node.address(), To: to, Value: "0x10"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.SendTx(tokenup_sdk.TransactRequest{From: node.address(), To: to, GasLimit: "0x186a0"}); err != nil {
+		t.Fatal(err)
+	}
+	var estimates []tokenup_sdk.EstimateRequest
+	node.decode("/v1/tx/estimate", &estimates)
+	sent := node.sent()
+	if estimates[0].Value != "0x10" {
+		t.Fatalf("estimate without value: %+v", estimates[0])
+	}
+	if sent[0].GasLimit != "0x7b0c" || sent[1].GasLimit != "0x186a0" {
+		t.Fatalf("gas limits %s %s", sent[0].GasLimit, sent[1].GasLimit)
+	}
+
+	// 节点估算值 21000 已经超过上限
+	client.GasLimitMargin = tokenup_sdk.GasLimitMargin{Multiplier: 1.5, Cap: 20000}
+	if _, err := client.SendTx(tokenup_sdk.TransactRequest{From: node.address(), To: to, Value: "0x10"}); err == nil || !strings.Contains(err.Error(), "exceeds GasLimitMargin.Cap") {
+		t.Fatalf("err %v", err)
+	}
+	if n := len(node.sent()); n != 2 {
+		t.Fatalf("%d transactions sent", n)
+	}
+}
@@ -0,0 +1,211 @@
+package tokenup_sdk
+
+import (
+	"crypto"
+	"crypto/rsa"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+var ErrNoSigningKey = errors.New("no active app signing key")
+
+// CallbackKey 回调方(签名服务/节点网关)的公钥，KeyId 用于匹配回调中携带的 key_id
+type CallbackKey struct {
+	KeyId     string
+	PublicKey string
+}
+
+// AppKey 应用签名私钥，Signer 不为 nil 时忽略 PrivateKey，私钥可以保存在 HSM 等外部设备中
+// ActiveAt 为通过 ScheduleAppKey 排队时的切换时间
+type AppKey struct {
+	KeyId      string
+	PrivateKey string
+	Signer     crypto.Signer
+	ActiveAt   time.Time
+}
+
+type callbackKey struct {
+	keyId string
+	pub   *rsa.PublicKey
+}
+
+type appKey struct {
+	keyId    string
+	signer   crypto.Signer
+	activeAt time.Time
+}
+
+// KeyStore 保存可信的回调公钥集合与应用签名私钥，支持运行时替换，无需重建 Client
+// 验签时优先使用 key_id 匹配的公钥，再按顺序尝试其余公钥；
+// 签名时使用当前生效的私钥，排队中的私钥在 ActiveAt 之后自动切换为生效私钥
+type KeyStore struct {
+	mu           sync.RWMutex
+	callbackKeys []callbackKey
+	active       *appKey
+	next         *appKey
+}
+
+func NewKeyStore() *KeyStore {
+	return &KeyStore{}
+}
+
+// SetCallbackKeys 整体替换可信回调公钥集合，任一公钥解析失败时保持原集合不变
+func (ks *KeyStore) SetCallbackKeys(keys ...CallbackKey) error {
+	parsed := make([]callbackKey, 0, len(keys))
+	for _, k := range keys {
+		pub, err := parseRsaPublicKey(k.PublicKey)
+		if err != nil {
+			return fmt.Errorf("callback key %q: %v", k.KeyId, err)
+		}
+		parsed = append(parsed, callbackKey{keyId: k.KeyId, pub: pub})
+	}
+	ks.mu.Lock()
+	ks.callbackKeys = parsed
+	ks.mu.Unlock()
+	return nil
+}
+
+// SetAppKey 立即切换生效的签名私钥，并清除排队中的私钥
+func (ks *KeyStore) SetAppKey(key AppKey) error {
+	k, err := newAppKey(key)
+	if err != nil {
+		return err
+	}
+	ks.mu.Lock()
+	ks.active = k
+	ks.next = nil
+	ks.mu.Unlock()
+	return nil
+}
+
+// ScheduleAppKey 排队下一把签名私钥，在 key.ActiveAt 之后的签名请求使用该私钥
+func (ks *KeyStore) ScheduleAppKey(key AppKey) error {
+	if key.ActiveAt.IsZero() {
+		return errors.New("scheduled app key requires ActiveAt")
+	}
+	k, err := newAppKey(key)
+	if err != nil {
+		return err
+	}
+	ks.mu.Lock()
+	ks.next = k
+	ks.mu.Unlock()
+	return nil
+}
+
+// ActiveKeyId 返回当前用于签名的私钥 KeyId
+func (ks *KeyStore) ActiveKeyId() string {
+	k := ks.signingKey(time.Now())
+	if k == nil {
+		return ""
+	}
+	return k.keyId
+}
+
+func (ks *KeyStore) Sign(data []byte) (string, error) {
+	k := ks.signingKey(time.Now())
+	if k == nil {
+		return "", ErrNoSigningKey
+	}
+	return rsaSign(k.signer, data)
+}
+
+func (ks *KeyStore) Verify(data []byte, signature, keyId string) error {
+	ks.mu.RLock()
+	keys := ks.callbackKeys
+	ks.mu.RUnlock()
+	if len(keys) == 0 {
+		return errors.New("no trusted callback public key")
+	}
+	var err error
+	if keyId != "" {
+		for _, k := range keys {
+			if k.keyId == keyId {
+				if err = rsaVerify(k.pub, data, signature); err == nil {
+					return nil
+				}
+				break
+			}
+		}
+	}
+	for _, k := range keys {
+		if keyId != "" && k.keyId == keyId {
+			continue
+		}
+		if err = rsaVerify(k.pub, data, signature); err == nil {
+			return nil
+		}
+	}
+	return err
+}
+
+func (ks *KeyStore) signingKey(now time.Time) *appKey {
+	ks.mu.RLock()
+	active, next := ks.active, ks.next
+	ks.mu.RUnlock()
+	if next == nil || now.Before(next.activeAt) {
+		return active
+	}
+	ks.mu.Lock()
+	defer ks.mu.Unlock()
+	if ks.next == next {
+		ks.active = next
+		ks.next = nil
+	}
+	return ks.active
+}
+
+func newAppKey(key AppKey) (*appKey, error) {
+	signer := key.Signer
+	if signer == nil {
+		priv, err := parseRsaPrivateKey(key.PrivateKey)
+		if err != nil {
+			return nil, fmt.Errorf("app key %q: %v", key.KeyId, err)
+		}
+		signer = priv
+	} else if err := checkRsaSigner(signer); err != nil {
+		return nil, fmt.Errorf("app key %q: %v", key.KeyId, err)
+	}
+	return &appKey{keyId: key.KeyId, signer: signer, activeAt: key.ActiveAt}, nil
+}
+
+// keyStore 返回 Client 的密钥，首次调用时解析 Authorize 中的密钥并缓存
+func (client *Client) keyStore() (*KeyStore, error) {
+	client.mu.Lock()
+	defer client.mu.Unlock()
+	if client.Keys != nil {
+		return client.Keys, nil
+	}
+	ks := NewKeyStore()
+	a := client.Authorize
+	if a.Signer != nil || a.PrivateKey != "" {
+		if err := ks.SetAppKey(AppKey{PrivateKey: a.PrivateKey, Signer: a.Signer}); err != nil {
+			return nil, err
+		}
+	}
+	if a.CallBackPartyPublicKey != "" {
+		if err := ks.SetCallbackKeys(CallbackKey{PublicKey: a.CallBackPartyPublicKey}); err != nil {
+			return nil, err
+		}
+	}
+	client.Keys = ks
+	return ks, nil
+}
+
+func (client *Client) sign(data []byte) (string, error) {
+	ks, err := client.keyStore()
+	if err != nil {
+		return "", err
+	}
+	return ks.Sign(data)
+}
+
+func (client *Client) verifyCallback(data []byte, signature, keyId string) error {
+	ks, err := client.keyStore()
+	if err != nil {
+		return err
+	}
+	return ks.Verify(data, signature, keyId)
+}
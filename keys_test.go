@@ -0,0 +1,66 @@
+package tokenup_sdk_test
+
+import (
+	"github.com/cblk/tokenup-sdk"
+	"testing"
+	"time"
+)
+
+func TestKeyStore_Rotation(t *testing.T) {
+	oldPriv, oldPub := newTestKeys(t)
+	newPriv, newPub := newTestKeys(t)
+	data := []byte("app_id=app&nonce=1")
+
+	ks := tokenup_sdk.NewKeyStore()
+	if _, err := ks.Sign(data); err != tokenup_sdk.ErrNoSigningKey {
+		t.Fatalf("want ErrNoSigningKey, got %v", err)
+	}
+	if err := ks.SetAppKey(tokenup_sdk.AppKey{KeyId: "old", PrivateKey: oldPriv}); err != nil {
+		t.Fatal(err)
+	}
+	if err := ks.ScheduleAppKey(tokenup_sdk.AppKey{KeyId: "new", PrivateKey: newPriv, ActiveAt: time.Now().Add(50 * time.Millisecond)}); err != nil {
+		t.Fatal(err)
+	}
+	if id := ks.ActiveKeyId(); id != "old" {
+		t.Fatalf("active key before cutover = %q", id)
+	}
+	time.Sleep(60 * time.Millisecond)
+	if id := ks.ActiveKeyId(); id != "new" {
+		t.Fatalf("active key after cutover = %q", id)
+	}
+
+	sig, err := tokenup_sdk.RsaSignAndPrivate(data, newPriv)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := ks.SetCallbackKeys(
+		tokenup_sdk.CallbackKey{KeyId: "k1", PublicKey: oldPub},
+		tokenup_sdk.CallbackKey{KeyId: "k2", PublicKey: newPub},
+	); err != nil {
+		t.Fatal(err)
+	}
+	for _, hint := range []string{"", "k1", "k2", "unknown"} {
+		if err := ks.Verify(data, sig, hint); err != nil {
+			t.Errorf("hint %q: %v", hint, err)
+		}
+	}
+	if err := ks.SetCallbackKeys(tokenup_sdk.CallbackKey{KeyId: "k1", PublicKey: oldPub}); err != nil {
+		t.Fatal(err)
+	}
+	if err := ks.Verify(data, sig, "k2"); err == nil {
+		t.Error("signature verified after its key was removed")
+	}
+}
+
+func TestNewClient_InvalidKey(t *testing.T) {
+	priv, pub := newTestKeys(t)
+	if _, err := tokenup_sdk.NewClient(tokenup_sdk.NodeConfig{}, tokenup_sdk.Authorize{PrivateKey: priv, CallBackPartyPublicKey: pub}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := tokenup_sdk.NewClient(tokenup_sdk.NodeConfig{}, tokenup_sdk.Authorize{PrivateKey: pub}); err == nil {
+		t.Error("public key accepted as app private key")
+	}
+	if err := tokenup_sdk.Init(&tokenup_sdk.Client{Authorize: tokenup_sdk.Authorize{CallBackPartyPublicKey: "invalid"}}); err == nil {
+		t.Error("invalid callback public key accepted")
+	}
+}
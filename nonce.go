@@ -0,0 +1,135 @@
+package tokenup_sdk
+
+import (
+	"sort"
+	"strings"
+	"sync"
+)
+
+// NonceManager 在本地为每个发送地址分配交易 nonce，保证同一地址的并发 SendTx 不会拿到相同的 nonce
+// 首次分配或 Reset 之后从节点同步 nonce；签名或广播失败的 nonce 通过 Release 归还并优先复用，避免出现空洞
+type NonceManager struct {
+	mu    sync.Mutex
+	addrs map[string]*addressNonce
+}
+
+type addressNonce struct {
+	mu       sync.Mutex
+	synced   bool
+	next     uint64
+	released []uint64
+	pending  map[uint64]struct{}
+}
+
+func NewNonceManager() *NonceManager {
+	return &NonceManager{addrs: make(map[string]*addressNonce)}
+}
+
+func (m *NonceManager) address(address string) *addressNonce {
+	address = strings.ToLower(address)
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	a, ok := m.addrs[address]
+	if !ok {
+		a = &addressNonce{pending: make(map[uint64]struct{})}
+		m.addrs[address] = a
+	}
+	return a
+}
+
+// Acquire 为 address 分配一个 nonce，需要同步时调用 fetch 获取节点上该地址的下一个 nonce
+func (m *NonceManager) Acquire(address string, fetch func() (uint64, error)) (uint64, error) {
+	a := m.address(address)
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if !a.synced {
+		next, err := fetch()
+		if err != nil {
+			return 0, err
+		}
+		// 节点 nonce 之前的都已上链；仍在签名中的 nonce 保留，其间的空洞作为可复用 nonce
+		a.next = next
+		for n := range a.pending {
+			if n < next {
+				delete(a.pending, n)
+			} else if n >= a.next {
+				a.next = n + 1
+			}
+		}
+		a.released = a.released[:0]
+		for n := next; n < a.next; n++ {
+			if _, ok := a.pending[n]; !ok {
+				a.released = append(a.released, n)
+			}
+		}
+		a.synced = true
+	}
+	var nonce uint64
+	if len(a.released) > 0 {
+		nonce = a.released[0]
+		a.released = a.released[1:]
+	} else {
+		nonce = a.next
+		a.next++
+	}
+	a.pending[nonce] = struct{}{}
+	return nonce, nil
+}
+
+// Release 归还未能广播的 nonce，后续 Acquire 优先复用
+func (m *NonceManager) Release(address string, nonce uint64) {
+	a := m.address(address)
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if _, ok := a.pending[nonce]; !ok {
+		return
+	}
+	delete(a.pending, nonce)
+	if nonce+1 == a.next {
+		a.next--
+		// 归还末尾的 nonce 后，连续的已归还 nonce 也一并收回
+		for len(a.released) > 0 && a.released[len(a.released)-1]+1 == a.next {
+			a.next--
+			a.released = a.released[:len(a.released)-1]
+		}
+		return
+	}
+	i := sort.Search(len(a.released), func(i int) bool { return a.released[i] >= nonce })
+	a.released = append(a.released, 0)
+	copy(a.released[i+1:], a.released[i:])
+	a.released[i] = nonce
+}
+
+// Confirm 标记 nonce 已被节点接受
+func (m *NonceManager) Confirm(address string, nonce uint64) {
+	a := m.address(address)
+	a.mu.Lock()
+	delete(a.pending, nonce)
+	a.mu.Unlock()
+}
+
+// Reset 丢弃本地状态，下次 Acquire 时重新从节点同步
+func (m *NonceManager) Reset(address string) {
+	a := m.address(address)
+	a.mu.Lock()
+	a.synced = false
+	a.mu.Unlock()
+}
+
+// Pending 返回已分配但尚未 Confirm 或 Release 的 nonce
+func (m *NonceManager) Pending(address string) []uint64 {
+	a := m.address(address)
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	nonces := make([]uint64, 0, len(a.pending))
+	for n := range a.pending {
+		nonces = append(nonces, n)
+	}
+	sort.Slice(nonces, func(i, j int) bool { return nonces[i] < nonces[j] })
+	return nonces
+}
+
+func isNonceError(err error) bool {
+	msg := strings.ToLower(err.Error())
+	return strings.Contains(msg, "nonce too low") || strings.Contains(msg, "nonce too high")
+}
@@ -0,0 +1,68 @@
+package tokenup_sdk_test
+
+import (
+	"github.com/cblk/tokenup-sdk"
+	"reflect"
+	"sync"
+	"testing"
+)
+
+func TestNonceManager(t *testing.T) {
+	m := tokenup_sdk.NewNonceManager()
+	fetches := 0
+	fetch := func(n uint64) func() (uint64, error) {
+		return func() (uint64, error) {
+			fetches++
+			return n, nil
+		}
+	}
+
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	seen := map[uint64]bool{}
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			n, err := m.Acquire("0xABC", fetch(5))
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			mu.Lock()
+			seen[n] = true
+			mu.Unlock()
+		}()
+	}
+	wg.Wait()
+	if len(seen) != 20 || !seen[5] || !seen[24] || fetches != 1 {
+		t.Fatalf("nonces=%v fetches=%d", seen, fetches)
+	}
+
+	// 签名失败的 nonce 归还后优先复用，末尾的 nonce 直接收回
+	m.Release("0xabc", 10)
+	m.Release("0xabc", 24)
+	if n, _ := m.Acquire("0xabc", fetch(0)); n != 10 {
+		t.Fatalf("want reused nonce 10, got %d", n)
+	}
+	if n, _ := m.Acquire("0xabc", fetch(0)); n != 24 {
+		t.Fatalf("want nonce 24, got %d", n)
+	}
+
+	for n := uint64(5); n < 23; n++ {
+		m.Confirm("0xabc", n)
+	}
+	m.Reset("0xabc")
+	// 节点只看到了 nonce 20，仍在签名中的 23、24 保留，21、22 作为空洞复用
+	var got []uint64
+	for i := 0; i < 3; i++ {
+		n, _ := m.Acquire("0xabc", fetch(21))
+		got = append(got, n)
+	}
+	if !reflect.DeepEqual(got, []uint64{21, 22, 25}) {
+		t.Fatalf("after resync got %v", got)
+	}
+	if p := m.Pending("0xabc"); !reflect.DeepEqual(p, []uint64{21, 22, 23, 24, 25}) {
+		t.Fatalf("pending %v", p)
+	}
+}
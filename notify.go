@@ -0,0 +1,102 @@
+package tokenup_sdk
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+)
+
+var ErrInvalidNotification = errors.New("invalid tx notification")
+
+type TxNotification struct {
+	TxHash       string       `json:"tx_hash" sign:"tx_hash" description:"交易哈希"`
+	Status       TxStatus     `json:"status" sign:"status" description:"交易状态：0=None 1=Pending 2=Confirmed 3=Failed"`
+	NotifyStatus NotifyStatus `json:"notify_status" sign:"notify_status" description:"通知状态：0=未通知 1=已通知"`
+	Type         TxType       `json:"type" sign:"type" description:"交易类型：0=创建合约 1=合约调用 2=转账"`
+	BlockNumber  uint64       `json:"block_number" sign:"block_number" description:"交易所在区块高度"`
+	GasUsed      string       `json:"gas_used" sign:"gas_used" description:"交易实际消耗的gas(16进制字符串)"`
+	Nonce        string       `json:"nonce" sign:"nonce"`
+	Timestamp    int64        `json:"timestamp" sign:"timestamp"`
+	AppKey       string       `json:"-" sign:"app_key"`
+	KeyId        string       `json:"key_id"`
+	Signature    string       `json:"signature"`
+}
+
+// TxNotifyHandler 接收节点网关的交易状态回调(TransactRequest.NotifyUrl / NodeConfig.NodeNotifyUrl)
+// 验签通过后按交易状态分发给对应的回调，OnNotify 对所有状态都会被调用
+// 回调返回错误时响应500并删除已记录的 nonce(需要 NonceCache 实现 NonceForgetter)，节点重试时可以重新处理
+type TxNotifyHandler struct {
+	Client      *Client
+	OnNotify    func(TxNotification) error
+	OnPending   func(TxNotification) error
+	OnConfirmed func(TxNotification) error
+	OnFailed    func(TxNotification) error
+}
+
+func (client *Client) ValidTxNotification(n *TxNotification) error {
+	if n.TxHash == "" || n.Signature == "" {
+		return ErrInvalidNotification
+	}
+	n.AppKey = client.Authorize.AppKey
+	plain, err := CanonicalString(n)
+	if err != nil {
+		return err
+	}
+	if err := client.verifyCallback([]byte(plain), n.Signature, n.KeyId); err != nil {
+		return err
+	}
+	return client.checkReplay(n.Nonce, n.Timestamp)
+}
+
+func (h *TxNotifyHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeNotifyResponse(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+	var n TxNotification
+	if err := json.NewDecoder(r.Body).Decode(&n); err != nil {
+		writeNotifyResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	client := h.Client
+	if client == nil {
+		client = GetClient()
+	}
+	if err := client.ValidTxNotification(&n); err != nil {
+		writeNotifyResponse(w, http.StatusUnauthorized, err.Error())
+		return
+	}
+	if err := h.dispatch(n); err != nil {
+		_ = client.forgetNonce(n.Nonce)
+		writeNotifyResponse(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	writeNotifyResponse(w, http.StatusOK, "success")
+}
+
+func (h *TxNotifyHandler) dispatch(n TxNotification) error {
+	if h.OnNotify != nil {
+		if err := h.OnNotify(n); err != nil {
+			return err
+		}
+	}
+	var cb func(TxNotification) error
+	switch n.Status {
+	case TxStatusPending:
+		cb = h.OnPending
+	case TxStatusConfirmed:
+		cb = h.OnConfirmed
+	case TxStatusFailed:
+		cb = h.OnFailed
+	}
+	if cb == nil {
+		return nil
+	}
+	return cb(n)
+}
+
+func writeNotifyResponse(w http.ResponseWriter, code int, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	_ = json.NewEncoder(w).Encode(Response{Message: message})
+}
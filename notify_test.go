@@ -0,0 +1,187 @@
+package tokenup_sdk_test
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"github.com/cblk/tokenup-sdk"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func newTestKeys(t *testing.T) (string, string) {
+	priv, err := rsa.GenerateKey(rand.Reader, 1024)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pub, err := x509.MarshalPKIXPublicKey(&priv.PublicKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return base64.StdEncoding.EncodeToString(x509.MarshalPKCS1PrivateKey(priv)),
+		base64.StdEncoding.EncodeToString(pub)
+}
+
+func signCallback(t *testing.T, v interface{}, priv string) string {
+	plain, err := tokenup_sdk.CanonicalString(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sig, err := tokenup_sdk.RsaSignAndPrivate([]byte(plain), priv)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return sig
+}
+
+func newTestClient(t *testing.T) (*tokenup_sdk.Client, string) {
+	priv, pub := newTestKeys(t)
+	return &tokenup_sdk.Client{
+		Authorize: tokenup_sdk.Authorize{
+			AppId:                  "app",
+			AppKey:                 "key",
+			CallBackPartyPublicKey: pub,
+		},
+	}, priv
+}
+
+func TestTxNotifyHandler(t *testing.T) {
+	client, partyKey := newTestClient(t)
+	n := tokenup_sdk.TxNotification{
+		TxHash:      "0xeb4fdc1cf0b518815f413baeb969b2bf2d1fff7ac9047a894ca523224f9c0657",
+		Status:      tokenup_sdk.TxStatusConfirmed,
+		BlockNumber: 100,
+		GasUsed:     "0x5208",
+		Nonce:       "1",
+		Timestamp:   time.Now().Unix(),
+		AppKey:      client.AppKey,
+	}
+	n.Signature = signCallback(t, n, partyKey)
+
+	var confirmed []string
+	h := &tokenup_sdk.TxNotifyHandler{
+		Client: client,
+		OnConfirmed: func(n tokenup_sdk.TxNotification) error {
+			confirmed = append(confirmed, n.TxHash)
+			return nil
+		},
+	}
+	body, _ := json.Marshal(n)
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/notify", bytes.NewReader(body)))
+	if w.Code != http.StatusOK || len(confirmed) != 1 {
+		t.Fatalf("code=%d confirmed=%v body=%s", w.Code, confirmed, w.Body.String())
+	}
+
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/notify", bytes.NewReader(body)))
+	if w.Code != http.StatusUnauthorized || len(confirmed) != 1 {
+		t.Fatalf("replayed notification accepted: code=%d", w.Code)
+	}
+
+	n.Status = tokenup_sdk.TxStatusFailed
+	body, _ = json.Marshal(n)
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/notify", bytes.NewReader(body)))
+	if w.Code != http.StatusUnauthorized {
+		t.Fatalf("tampered notification accepted: code=%d", w.Code)
+	}
+}
+
+func TestValidTxNotification_Replay(t *testing.T) {
+	client, partyKey := newTestClient(t)
+	sign := func(n tokenup_sdk.TxNotification) *tokenup_sdk.TxNotification {
+		n.AppKey = client.AppKey
+		n.Signature = signCallback(t, n, partyKey)
+		return &n
+	}
+	fresh := tokenup_sdk.TxNotification{TxHash: "0x01", Nonce: "n1", Timestamp: time.Now().Unix()}
+	if err := client.ValidTxNotification(sign(fresh)); err != nil {
+		t.Fatal(err)
+	}
+	if err := client.ValidTxNotification(sign(fresh)); err != tokenup_sdk.ErrCallbackReplayed {
+		t.Fatalf("want ErrCallbackReplayed, got %v", err)
+	}
+	stale := tokenup_sdk.TxNotification{TxHash: "0x01", Nonce: "n2", Timestamp: time.Now().Add(-time.Hour).Unix()}
+	if err := client.ValidTxNotification(sign(stale)); err != tokenup_sdk.ErrCallbackExpired {
+		t.Fatalf("want ErrCallbackExpired, got %v", err)
+	}
+}
+
+func TestTxNotifyHandler_RetryAfterCallbackError(t *testing.T) {
+	client, partyKey := newTestClient(t)
+	n := tokenup_sdk.TxNotification{
+		TxHash:    "0x01",
+		Status:    tokenup_sdk.TxStatusConfirmed,
+		Nonce:     "retry",
+		Timestamp: time.Now().Unix(),
+		AppKey:    client.AppKey,
+	}
+	n.Signature = signCallback(t, n, partyKey)
+	body, _ := json.Marshal(n)
+
+	calls := 0
+	h := &tokenup_sdk.TxNotifyHandler{
+		Client: client,
+		OnConfirmed: func(tokenup_sdk.TxNotification) error {
+			calls++
+			if calls == 1 {
+				return errors.New("db unavailable")
+			}
+			return nil
+		},
+	}
+	for _, want := range []int{http.StatusInternalServerError, http.StatusOK, http.StatusUnauthorized} {
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/notify", bytes.NewReader(body)))
+		if w.Code != want {
+			t.Fatalf("want %d, got %d %s", want, w.Code, w.Body.String())
+		}
+	}
+	if calls != 2 {
+		t.Fatalf("callback called %d times", calls)
+	}
+}
+
+func TestValidTxNotification_EmptyNonce(t *testing.T) {
+	client, partyKey := newTestClient(t)
+	n := tokenup_sdk.TxNotification{TxHash: "0x01", Timestamp: time.Now().Unix(), AppKey: client.AppKey}
+	n.Signature = signCallback(t, n, partyKey)
+	if err := client.ValidTxNotification(&n); err != tokenup_sdk.ErrCallbackNoNonce {
+		t.Fatalf("want ErrCallbackNoNonce, got %v", err)
+	}
+}
+
+func TestValidTxNotification_NonceCacheFull(t *testing.T) {
+	client, partyKey := newTestClient(t)
+	client.NonceCache = tokenup_sdk.NewLRUNonceCache(1)
+	sign := func(nonce string) *tokenup_sdk.TxNotification {
+		n := tokenup_sdk.TxNotification{TxHash: "0x01", Nonce: nonce, Timestamp: time.Now().Unix(), AppKey: client.AppKey}
+		n.Signature = signCallback(t, n, partyKey)
+		return &n
+	}
+	if err := client.ValidTxNotification(sign("n1")); err != nil {
+		t.Fatal(err)
+	}
+	if err := client.ValidTxNotification(sign("n2")); err != tokenup_sdk.ErrNonceCacheFull {
+		t.Fatalf("want ErrNonceCacheFull, got %v", err)
+	}
+	if err := client.ValidTxNotification(sign("n1")); err != tokenup_sdk.ErrCallbackReplayed {
+		t.Fatalf("n1 evicted while unexpired: %v", err)
+	}
+
+	cache := tokenup_sdk.NewLRUNonceCache(1)
+	if _, err := cache.Seen("old", time.Millisecond); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(5 * time.Millisecond)
+	if seen, err := cache.Seen("new", time.Minute); seen || err != nil {
+		t.Fatalf("expired nonce not evicted: %v %v", seen, err)
+	}
+}
@@ -0,0 +1,129 @@
+package tokenup_sdk
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"strings"
+	"time"
+)
+
+const (
+	OfflineTxVersion  = 1
+	offlineTextPrefix = "tokenup-tx:"
+)
+
+// UnsignedTx 离线签名的交易导出格式
+// Hash 为需要离线签名的 EIP-155 哈希，签名结果为65字节 [R || S || V] 的16进制字符串
+// RawUnsigned 为未签名交易 [nonce, gasPrice, gas, to, value, data, chainId, 0, 0] 的 RLP 编码(16进制字符串)，
+// 其 keccak256 等于 Hash，离线签名端可以据此自行解码核对交易内容
+type UnsignedTx struct {
+	Version     int               `json:"version"`
+	ChainId     int64             `json:"chain_id"`
+	Request     TransactRequest   `json:"request"`
+	Hash        string            `json:"hash"`
+	RawUnsigned string            `json:"raw_unsigned"`
+	Metadata    map[string]string `json:"metadata,omitempty"`
+	CreatedAt   int64             `json:"created_at"`
+}
+
+// ExportTx 通过 tx/estimate 补全交易并导出待签名数据，不请求签名服务
+// 配置了 Nonces 时分配的 nonce 保持占用，交易最终不广播时需要调用 NonceManager.Release 归还
+func (client *Client) ExportTx(req TransactRequest, metadata map[string]string) (UnsignedTx, error) {
+	req, chainId, _, err := client.fillTx(context.Background(), req)
+	if err != nil {
+		return UnsignedTx{}, err
+	}
+	req.Signature = ""
+	hash, err := req.decode(chainId)
+	if err != nil {
+		return UnsignedTx{}, err
+	}
+	raw, err := req.unsignedRLP(chainId)
+	if err != nil {
+		return UnsignedTx{}, err
+	}
+	return UnsignedTx{
+		Version:     OfflineTxVersion,
+		ChainId:     chainId,
+		Request:     req,
+		Hash:        hash,
+		RawUnsigned: hexutil.Encode(raw),
+		Metadata:    metadata,
+		CreatedAt:   time.Now().Unix(),
+	}, nil
+}
+
+// BroadcastSigned 校验离线签名能恢复出 Request.From 后通过 tx/transact 广播
+func (client *Client) BroadcastSigned(u UnsignedTx, signature string) (TransactResponse, error) {
+	prepared, err := u.Attach(signature)
+	if err != nil {
+		return TransactResponse{}, err
+	}
+	return client.transact(context.Background(), prepared.Request)
+}
+
+// Attach 校验导出数据未被篡改(Hash 与 RawUnsigned 都必须与 Request 一致)且签名能恢复出 Request.From，返回已签名交易
+func (u UnsignedTx) Attach(signature string) (PreparedTx, error) {
+	if u.Version != OfflineTxVersion {
+		return PreparedTx{}, fmt.Errorf("unsupported offline tx version %d", u.Version)
+	}
+	req := u.Request
+	req.Signature = ""
+	hash, err := req.decode(u.ChainId)
+	if err != nil {
+		return PreparedTx{}, err
+	}
+	if !strings.EqualFold(hash, u.Hash) {
+		return PreparedTx{}, errors.New("offline tx hash does not match its request")
+	}
+	if u.RawUnsigned == "" {
+		return PreparedTx{}, errors.New("offline tx missing raw_unsigned")
+	}
+	raw, err := req.unsignedRLP(u.ChainId)
+	if err != nil {
+		return PreparedTx{}, err
+	}
+	if !strings.EqualFold(hexutil.Encode(raw), u.RawUnsigned) {
+		return PreparedTx{}, errors.New("offline tx raw_unsigned does not match its request")
+	}
+	req.Signature = normalizeSignature(signature)
+	return newPreparedTx(req, u.ChainId)
+}
+
+// EncodeText 返回适合复制粘贴或生成二维码的单行文本
+func (u UnsignedTx) EncodeText() (string, error) {
+	b, err := json.Marshal(u)
+	if err != nil {
+		return "", err
+	}
+	return offlineTextPrefix + base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// DecodeUnsignedTx 解析 JSON 或 EncodeText 生成的文本
+func DecodeUnsignedTx(s string) (UnsignedTx, error) {
+	s = strings.TrimSpace(s)
+	b := []byte(s)
+	if strings.HasPrefix(s, offlineTextPrefix) {
+		var err error
+		if b, err = base64.RawURLEncoding.DecodeString(strings.TrimPrefix(s, offlineTextPrefix)); err != nil {
+			return UnsignedTx{}, fmt.Errorf("invalid offline tx text: %v", err)
+		}
+	}
+	var u UnsignedTx
+	if err := json.Unmarshal(b, &u); err != nil {
+		return UnsignedTx{}, fmt.Errorf("invalid offline tx: %v", err)
+	}
+	return u, nil
+}
+
+func normalizeSignature(signature string) string {
+	signature = strings.TrimSpace(signature)
+	if !strings.HasPrefix(signature, "0x") && !strings.HasPrefix(signature, "0X") {
+		signature = "0x" + signature
+	}
+	return strings.ToLower(signature)
+}
@@ -0,0 +1,64 @@
+package tokenup_sdk_test
+
+import (
+	"github.com/cblk/tokenup-sdk"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"testing"
+)
+
+func TestOfflineTx(t *testing.T) {
+	node, client := newFakeNode(t)
+	node.acceptTx()
+	to := "0xdF0F1b2Fa2992247ffC68790B20a3Df1E7514B68"
+	u, err := client.ExportTx(tokenup_sdk.TransactRequest{From: node.address(), To: to, Value: "0x1"}, map[string]string{"memo": "payout"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	text, err := u.EncodeText()
+	if err != nil {
+		t.Fatal(err)
+	}
+	decoded, err := tokenup_sdk.DecodeUnsignedTx(text)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if decoded.Hash != u.Hash || decoded.Request.Nonce != 7 || decoded.Metadata["memo"] != "payout" {
+		t.Fatalf("decoded %+v", decoded)
+	}
+	if h := crypto.Keccak256Hash(hexutil.MustDecode(u.RawUnsigned)); h.Hex() != u.Hash || decoded.RawUnsigned != u.RawUnsigned {
+		t.Fatalf("raw_unsigned %s hashes to %s, want %s", u.RawUnsigned, h.Hex(), u.Hash)
+	}
+
+	other, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	bad, _ := crypto.Sign(hexutil.MustDecode(u.Hash), other)
+	if _, err := client.BroadcastSigned(decoded, hexutil.Encode(bad)); err == nil {
+		t.Fatal("signature from another key accepted")
+	}
+	tampered := decoded
+	tampered.Request.Value = "0x2"
+	sig, _ := crypto.Sign(hexutil.MustDecode(u.Hash), node.key)
+	if _, err := tampered.Attach(hexutil.Encode(sig)); err == nil {
+		t.Fatal("tampered request accepted")
+	}
+	tampered = decoded
+	tampered.RawUnsigned = u.RawUnsigned[:len(u.RawUnsigned)-2] + "01"
+	if _, err := tampered.Attach(hexutil.Encode(sig)); err == nil {
+		t.Fatal("tampered raw_unsigned accepted")
+	}
+	tampered.RawUnsigned = ""
+	if _, err := tampered.Attach(hexutil.Encode(sig)); err == nil {
+		t.Fatal("missing raw_unsigned accepted")
+	}
+
+	if _, err := client.BroadcastSigned(decoded, hexutil.Encode(sig)[2:]); err != nil {
+		t.Fatal(err)
+	}
+	sent := node.sent()
+	if len(sent) != 1 || sent[0].Signature != hexutil.Encode(sig) {
+		t.Fatalf("sent %+v", sent)
+	}
+}
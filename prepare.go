@@ -0,0 +1,95 @@
+package tokenup_sdk
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rlp"
+	"math/big"
+	"strings"
+)
+
+// PreparedTx 已签名但未广播的交易
+// Hash 为签名服务签名的 EIP-155 哈希，TxHash 为签名后交易的哈希，RawTx 为 RLP 编码的已签名交易(16进制字符串)，
+// 可以通过任意渠道(如 eth_sendRawTransaction)广播
+type PreparedTx struct {
+	Request   TransactRequest
+	ChainId   int64
+	Hash      string
+	Signature string
+	TxHash    string
+	RawTx     string
+}
+
+// PrepareTx 与 SendTx 一样完成估算、nonce、gas price 与签名，但不调用 tx/transact
+// 签名需要是65字节 [R || S || V] 且能恢复出 From，用于组装 RawTx；SendTx 不做该检查，签名原样交给节点
+// 配置了 Nonces 时分配的 nonce 保持占用，交易最终不广播时需要调用 NonceManager.Release 归还
+func (client *Client) PrepareTx(req TransactRequest) (PreparedTx, error) {
+	req, chainId, managed, err := client.prepareTx(context.Background(), req)
+	if err != nil {
+		return PreparedTx{}, err
+	}
+	prepared, err := newPreparedTx(req, chainId)
+	if err != nil && managed {
+		client.Nonces.Release(req.From, req.Nonce)
+	}
+	return prepared, err
+}
+
+func newPreparedTx(req TransactRequest, chainId int64) (PreparedTx, error) {
+	signer := types.NewEIP155Signer(big.NewInt(chainId))
+	tx, err := req.transaction()
+	if err != nil {
+		return PreparedTx{}, err
+	}
+	hash := signer.Hash(tx)
+	signed, err := signTransaction(tx, signer, req.Signature)
+	if err != nil {
+		return PreparedTx{}, err
+	}
+	if err := checkSender(signer, signed, req.From); err != nil {
+		return PreparedTx{}, err
+	}
+	raw, err := rlp.EncodeToBytes(signed)
+	if err != nil {
+		return PreparedTx{}, err
+	}
+	return PreparedTx{
+		Request:   req,
+		ChainId:   chainId,
+		Hash:      hexutil.Encode(hash[:]),
+		Signature: req.Signature,
+		TxHash:    signed.Hash().Hex(),
+		RawTx:     hexutil.Encode(raw),
+	}, nil
+}
+
+// signTransaction 将65字节 [R || S || V] 签名附加到交易上，V 可以为 0/1 或 27/28
+func signTransaction(tx *types.Transaction, signer types.EIP155Signer, signature string) (*types.Transaction, error) {
+	sig, err := hex.DecodeString(strings.TrimPrefix(strings.TrimPrefix(signature, "0x"), "0X"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid signature %q: %v", signature, err)
+	}
+	if len(sig) != 65 {
+		return nil, fmt.Errorf("invalid signature length %d, want 65", len(sig))
+	}
+	sig = append([]byte(nil), sig...)
+	if sig[64] >= 27 {
+		sig[64] -= 27
+	}
+	return tx.WithSignature(signer, sig)
+}
+
+func checkSender(signer types.EIP155Signer, tx *types.Transaction, from string) error {
+	sender, err := types.Sender(signer, tx)
+	if err != nil {
+		return err
+	}
+	if !strings.EqualFold(sender.Hex(), common.HexToAddress(from).Hex()) {
+		return fmt.Errorf("signature recovers to %s, want %s", sender.Hex(), from)
+	}
+	return nil
+}
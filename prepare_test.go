@@ -0,0 +1,64 @@
+package tokenup_sdk_test
+
+import (
+	"github.com/cblk/tokenup-sdk"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rlp"
+	"math/big"
+	"strings"
+	"testing"
+)
+
+func TestPrepareTx(t *testing.T) {
+	node, client := newFakeNode(t)
+	node.reply("/v1/tx/estimate", estimateData())
+	to := "0xdF0F1b2Fa2992247ffC68790B20a3Df1E7514B68"
+	prepared, err := client.PrepareTx(tokenup_sdk.TransactRequest{From: node.address(), To: to, Value: "0xde0b6b3a7640000"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if node.count("/v1/tx/transact") != 0 {
+		t.Fatal("PrepareTx broadcast the transaction")
+	}
+	req := prepared.Request
+	if req.Nonce != 7 || req.GasPrice != "0x2540be400" || req.GasLimit != "0x5208" || req.Signature == "" {
+		t.Fatalf("request %+v", req)
+	}
+
+	var tx types.Transaction
+	if err := rlp.DecodeBytes(hexutil.MustDecode(prepared.RawTx), &tx); err != nil {
+		t.Fatal(err)
+	}
+	eip155 := types.NewEIP155Signer(big.NewInt(prepared.ChainId))
+	sender, err := types.Sender(eip155, &tx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.EqualFold(sender.Hex(), node.address()) || tx.Hash().Hex() != prepared.TxHash {
+		t.Fatalf("sender %s hash %s", sender.Hex(), tx.Hash().Hex())
+	}
+	if h := eip155.Hash(&tx); hexutil.Encode(h[:]) != prepared.Hash {
+		t.Fatalf("signing hash %x != %s", h, prepared.Hash)
+	}
+	if tx.Value().String() != "1000000000000000000" || tx.Nonce() != 7 {
+		t.Fatalf("tx value %v nonce %d", tx.Value(), tx.Nonce())
+	}
+}
+
+func TestSendTx_ForwardsSignerOutput(t *testing.T) {
+	node, client := newFakeNode(t)
+	node.acceptTx()
+	// 签名服务托管的地址与 From 不同时签名无法恢复出 From，SendTx 不做检查，由节点判断
+	req := tokenup_sdk.TransactRequest{From: "0xdF0F1b2Fa2992247ffC68790B20a3Df1E7514B68", To: node.address(), Value: "0x1"}
+	if _, err := client.SendTx(req); err != nil {
+		t.Fatal(err)
+	}
+	sent, signed := node.sent(), node.signed()
+	if len(sent) != 1 || len(signed) != 1 || sent[0].Signature != signed[0] {
+		t.Fatalf("sent %+v, signer returned %v", sent, signed)
+	}
+	if _, err := client.PrepareTx(req); err == nil {
+		t.Fatal("PrepareTx accepted a signature that does not recover to From")
+	}
+}
@@ -0,0 +1,160 @@
+package tokenup_sdk
+
+import (
+	"errors"
+	"fmt"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"math/big"
+	"strings"
+	"unicode"
+)
+
+var (
+	ErrReceiptNotFound = errors.New("transaction receipt not found")
+	ErrUnknownEvent    = errors.New("log does not match any event in the ABI")
+)
+
+type Receipt struct {
+	BlockNumber       uint64 `json:"block_number" description:"区块高度"`
+	BlockHash         string `json:"block_hash" description:"区块哈希"`
+	TxIndex           uint   `json:"tx_index" description:"交易在区块中的序号"`
+	Status            uint64 `json:"status" description:"执行结果：1=成功 0=失败"`
+	GasUsed           string `json:"gas_used" description:"交易实际消耗的gas(16进制字符串)"`
+	EffectiveGasPrice string `json:"effective_gas_price" description:"交易实际的gas价格(单位Wei，16进制字符串)"`
+	ContractAddress   string `json:"contract_address" description:"创建合约交易部署的合约地址"`
+	Logs              []Log  `json:"logs" description:"交易产生的事件日志"`
+}
+
+type Log struct {
+	Address     string   `json:"address" description:"事件对应的合约地址"`
+	Topics      []string `json:"topics" description:"事件的topic列表"`
+	Data        string   `json:"data" description:"事件数据(16进制字符串)"`
+	BlockNumber uint64   `json:"block_number" description:"区块高度"`
+	TxHash      string   `json:"tx_hash" description:"事件对应的交易哈希"`
+	LogIndex    uint     `json:"log_index" description:"事件在区块中的序列号"`
+}
+
+// Log 将 EventQuery 返回的事件转换为 Log，Topics 可以是 JSON 数组或以逗号/空格分隔的字符串
+func (e Event) Log() Log {
+	return Log{
+		Address:     e.Address,
+		Topics:      parseTopics(e.Topics),
+		Data:        e.Data,
+		BlockNumber: e.BlockNumber,
+		TxHash:      e.TxHash,
+		LogIndex:    e.LogIndex,
+	}
+}
+
+func parseTopics(s string) []string {
+	return strings.FieldsFunc(s, func(r rune) bool {
+		return r == '[' || r == ']' || r == '"' || r == ',' || unicode.IsSpace(r)
+	})
+}
+
+// DecodedLog 按 ABI 解码后的事件，Args 包含 indexed 与非 indexed 参数
+type DecodedLog struct {
+	Event string
+	Args  map[string]interface{}
+	Log   Log
+}
+
+// TxReceipt 返回已上链交易的收据，交易尚未上链时返回 ErrReceiptNotFound
+func (client *Client) TxReceipt(txHash string) (*Receipt, error) {
+	detail, err := client.TxDetail(txHash)
+	if err != nil {
+		return nil, err
+	}
+	if detail.Data.Receipt == nil {
+		return nil, ErrReceiptNotFound
+	}
+	return detail.Data.Receipt, nil
+}
+
+// FeePaid 交易实际支付的手续费(Wei)，为 GasUsed * EffectiveGasPrice，
+// 节点未返回 EffectiveGasPrice 时使用交易的 GasPrice
+func (res DetailResponse) FeePaid() (*big.Int, error) {
+	r := res.Data.Receipt
+	if r == nil {
+		return nil, ErrReceiptNotFound
+	}
+	price := r.EffectiveGasPrice
+	if price == "" {
+		price = res.Data.GasPrice
+	}
+	return r.fee(price)
+}
+
+// Fee 交易实际支付的手续费(Wei)，需要节点返回 EffectiveGasPrice
+func (r Receipt) Fee() (*big.Int, error) {
+	return r.fee(r.EffectiveGasPrice)
+}
+
+func (r Receipt) fee(gasPrice string) (*big.Int, error) {
+	gasUsed, err := hexutil.DecodeBig(r.GasUsed)
+	if err != nil {
+		return nil, fmt.Errorf("invalid gas_used %q: %v", r.GasUsed, err)
+	}
+	price, err := hexutil.DecodeBig(gasPrice)
+	if err != nil {
+		return nil, fmt.Errorf("invalid gas price %q: %v", gasPrice, err)
+	}
+	return new(big.Int).Mul(gasUsed, price), nil
+}
+
+// DecodeLogs 用 contractABI 解码收据中的事件日志，忽略 ABI 中不存在的事件
+func (r Receipt) DecodeLogs(contractABI abi.ABI) ([]DecodedLog, error) {
+	var decoded []DecodedLog
+	for _, l := range r.Logs {
+		d, err := DecodeLog(contractABI, l)
+		if err == ErrUnknownEvent {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		decoded = append(decoded, d)
+	}
+	return decoded, nil
+}
+
+// DecodeLog 按 contractABI 中与 topics[0] 对应的事件解码日志
+func DecodeLog(contractABI abi.ABI, l Log) (DecodedLog, error) {
+	if len(l.Topics) == 0 {
+		return DecodedLog{}, ErrUnknownEvent
+	}
+	topics := make([]common.Hash, len(l.Topics))
+	for i, t := range l.Topics {
+		b, err := hexutil.Decode(t)
+		if err != nil || len(b) != common.HashLength {
+			return DecodedLog{}, fmt.Errorf("invalid topic %q", t)
+		}
+		topics[i] = common.BytesToHash(b)
+	}
+	event, err := contractABI.EventByID(topics[0])
+	if err != nil {
+		return DecodedLog{}, ErrUnknownEvent
+	}
+	var data []byte
+	if l.Data != "" && l.Data != "0x" {
+		if data, err = hexutil.Decode(l.Data); err != nil {
+			return DecodedLog{}, fmt.Errorf("invalid log data: %v", err)
+		}
+	}
+	args := map[string]interface{}{}
+	if err := event.Inputs.NonIndexed().UnpackIntoMap(args, data); err != nil {
+		return DecodedLog{}, err
+	}
+	var indexed abi.Arguments
+	for _, arg := range event.Inputs {
+		if arg.Indexed {
+			indexed = append(indexed, arg)
+		}
+	}
+	if err := abi.ParseTopicsIntoMap(args, indexed, topics[1:]); err != nil {
+		return DecodedLog{}, err
+	}
+	return DecodedLog{Event: event.Name, Args: args, Log: l}, nil
+}
@@ -0,0 +1,63 @@
+package tokenup_sdk_test
+
+import (
+	"github.com/cblk/tokenup-sdk"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"math/big"
+	"strings"
+	"testing"
+)
+
+const transferEventABI = `[{"anonymous":false,"inputs":[{"indexed":true,"name":"from","type":"address"},{"indexed":true,"name":"to","type":"address"},{"indexed":false,"name":"value","type":"uint256"}],"name":"Transfer","type":"event"}]`
+
+func TestReceipt_DecodeLogs(t *testing.T) {
+	contractABI, err := abi.JSON(strings.NewReader(transferEventABI))
+	if err != nil {
+		t.Fatal(err)
+	}
+	from := common.HexToAddress("0xdF0F1b2Fa2992247ffC68790B20a3Df1E7514B68")
+	to := common.HexToAddress("0x0000000000000000000000000000000000000001")
+	r := tokenup_sdk.Receipt{
+		GasUsed:           "0x5208",
+		EffectiveGasPrice: "0x3b9aca00",
+		Logs: []tokenup_sdk.Log{
+			{
+				Topics: []string{
+					contractABI.Events["Transfer"].ID.Hex(),
+					common.BytesToHash(from.Bytes()).Hex(),
+					common.BytesToHash(to.Bytes()).Hex(),
+				},
+				Data: hexutil.Encode(common.LeftPadBytes(big.NewInt(1000).Bytes(), 32)),
+			},
+			{Topics: []string{common.HexToHash("0x01").Hex()}},
+		},
+	}
+	logs, err := r.DecodeLogs(contractABI)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(logs) != 1 || logs[0].Event != "Transfer" {
+		t.Fatalf("decoded %+v", logs)
+	}
+	args := logs[0].Args
+	if args["from"] != from || args["to"] != to || args["value"].(*big.Int).Int64() != 1000 {
+		t.Fatalf("args %+v", args)
+	}
+
+	fee, err := r.Fee()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fee.String() != "21000000000000" {
+		t.Fatalf("fee %v", fee)
+	}
+	var detail tokenup_sdk.DetailResponse
+	detail.Data.GasPrice = "0x77359400"
+	r.EffectiveGasPrice = ""
+	detail.Data.Receipt = &r
+	if fee, err := detail.FeePaid(); err != nil || fee.String() != "42000000000000" {
+		t.Fatalf("fee paid %v %v", fee, err)
+	}
+}
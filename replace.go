@@ -0,0 +1,200 @@
+package tokenup_sdk
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"math/big"
+	"sync"
+	"time"
+)
+
+const (
+	defaultReplaceBumpPercent = 10
+	transferGasLimit          = 21000
+)
+
+var ErrTxNotPending = errors.New("transaction is not pending")
+
+type ReplaceKind int
+
+const (
+	ReplaceSpeedUp ReplaceKind = iota + 1
+	ReplaceCancel
+)
+
+func (k ReplaceKind) String() string {
+	switch k {
+	case ReplaceSpeedUp:
+		return "speed_up"
+	case ReplaceCancel:
+		return "cancel"
+	}
+	return fmt.Sprintf("ReplaceKind(%d)", int(k))
+}
+
+// TxReplacement 一次交易替换记录，OldHash 被 NewHash 以相同 nonce 替换
+type TxReplacement struct {
+	OldHash    string
+	NewHash    string
+	Kind       ReplaceKind
+	Nonce      uint64
+	GasPrice   string
+	ReplacedAt time.Time
+}
+
+type txLineage struct {
+	mu       sync.Mutex
+	byOld    map[string]TxReplacement
+	previous map[string]string
+}
+
+// SpeedUp 以更高的 gas price 和相同的 nonce 重新发送一笔 pending 交易，strategy 为 nil 时使用 Client 配置
+// 新 gas price 不低于原交易的 (100+ReplaceBumpPercent)%
+func (client *Client) SpeedUp(txHash string, strategy GasStrategy) (TxReplacement, error) {
+	return client.replaceTx(txHash, strategy, ReplaceSpeedUp)
+}
+
+// Cancel 以相同的 nonce 向发送方自身发送一笔0值交易来取消 pending 交易
+func (client *Client) Cancel(txHash string) (TxReplacement, error) {
+	return client.replaceTx(txHash, nil, ReplaceCancel)
+}
+
+// TxLineage 返回包含 txHash 的替换链，按替换顺序排列，交易未被替换过时返回空
+func (client *Client) TxLineage(txHash string) []TxReplacement {
+	l := client.lineage()
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	root := txHash
+	for {
+		prev, ok := l.previous[root]
+		if !ok {
+			break
+		}
+		root = prev
+	}
+	var chain []TxReplacement
+	for {
+		r, ok := l.byOld[root]
+		if !ok {
+			return chain
+		}
+		chain = append(chain, r)
+		root = r.NewHash
+	}
+}
+
+// ReplacedBy 返回替换了 txHash 的交易哈希
+func (client *Client) ReplacedBy(txHash string) (string, bool) {
+	l := client.lineage()
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	r, ok := l.byOld[txHash]
+	return r.NewHash, ok
+}
+
+func (client *Client) replaceTx(txHash string, strategy GasStrategy, kind ReplaceKind) (TxReplacement, error) {
+	detail, err := client.TxDetail(txHash)
+	if err != nil {
+		return TxReplacement{}, err
+	}
+	orig := detail.Data
+	if orig.Status != TxStatusPending {
+		return TxReplacement{}, ErrTxNotPending
+	}
+	req := TransactRequest{
+		From:      orig.From,
+		To:        orig.To,
+		Nonce:     orig.Nonce,
+		Data:      orig.Data,
+		Value:     orig.Value,
+		GasLimit:  orig.GasLimit,
+		NotifyUrl: client.NodeNotifyUrl,
+	}
+	if kind == ReplaceCancel {
+		req.To = orig.From
+		req.Data = ""
+		req.Value = "0x0"
+		req.GasLimit = hexutil.EncodeUint64(transferGasLimit)
+	}
+	estimateResponse, err := client.Estimate(EstimateRequest{
+		From:        req.From,
+		To:          req.To,
+		Data:        req.Data,
+		Value:       req.Value,
+		FeeLimit:    client.FeeLimit,
+		GasPriceMax: client.GasPriceMax,
+		GasPriceMin: client.GasPriceMin,
+	})
+	if err != nil {
+		return TxReplacement{}, err
+	}
+	price, err := client.gasPrice(strategy, estimateResponse.Data.GasPrice)
+	if err != nil {
+		return TxReplacement{}, err
+	}
+	if req.GasPrice, err = client.bumpGasPrice(orig.GasPrice, price); err != nil {
+		return TxReplacement{}, err
+	}
+	if err := client.checkFeeCap(req); err != nil {
+		return TxReplacement{}, err
+	}
+	if err := client.signTx(context.Background(), &req, estimateResponse.Data.ChainId); err != nil {
+		return TxReplacement{}, err
+	}
+	res, err := client.transact(context.Background(), req)
+	if err != nil {
+		return TxReplacement{}, err
+	}
+	r := TxReplacement{
+		OldHash:    txHash,
+		NewHash:    res.Data.TxHash,
+		Kind:       kind,
+		Nonce:      req.Nonce,
+		GasPrice:   req.GasPrice,
+		ReplacedAt: time.Now(),
+	}
+	l := client.lineage()
+	l.mu.Lock()
+	l.byOld[r.OldHash] = r
+	l.previous[r.NewHash] = r.OldHash
+	l.mu.Unlock()
+	return r, nil
+}
+
+// bumpGasPrice 返回 price 与原 gas price 提高 ReplaceBumpPercent 后两者中的较大值
+func (client *Client) bumpGasPrice(original, price string) (string, error) {
+	orig, err := hexutil.DecodeBig(original)
+	if err != nil {
+		return "", fmt.Errorf("invalid original gas price %q: %v", original, err)
+	}
+	p, err := hexutil.DecodeBig(price)
+	if err != nil {
+		return "", fmt.Errorf("invalid gas price %q: %v", price, err)
+	}
+	bump := client.ReplaceBumpPercent
+	if bump <= 0 {
+		bump = defaultReplaceBumpPercent
+	}
+	// 向上取整，避免因舍入导致低于节点要求的最小涨幅
+	min := new(big.Int).Mul(orig, big.NewInt(100+bump))
+	min.Add(min, big.NewInt(99))
+	min.Div(min, big.NewInt(100))
+	if p.Cmp(min) < 0 {
+		p = min
+	}
+	return hexutil.EncodeBig(p), nil
+}
+
+func (client *Client) lineage() *txLineage {
+	client.mu.Lock()
+	defer client.mu.Unlock()
+	if client.replacements == nil {
+		client.replacements = &txLineage{
+			byOld:    make(map[string]TxReplacement),
+			previous: make(map[string]string),
+		}
+	}
+	return client.replacements
+}
@@ -0,0 +1,65 @@
+package tokenup_sdk_test
+
+import (
+	"github.com/cblk/tokenup-sdk"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestSpeedUpAndCancel(t *testing.T) {
+	node, client := newFakeNode(t)
+	node.acceptTx()
+	node.handle("/v1/tx/", func(path string, _ []byte) (int, interface{}) {
+		hash := strings.TrimPrefix(path, "/v1/tx/")
+		for _, req := range node.sent() {
+			if txHash(req) == hash {
+				return http.StatusOK, map[string]interface{}{
+					"from": req.From, "to": req.To, "nonce": req.Nonce, "data": req.Data, "value": req.Value,
+					"gas_price": req.GasPrice, "gas_limit": req.GasLimit, "tx_hash": hash, "status": 1,
+				}
+			}
+		}
+		return http.StatusNotFound, "not found"
+	})
+	last := func() tokenup_sdk.TransactRequest {
+		sent := node.sent()
+		return sent[len(sent)-1]
+	}
+	to := "0xdF0F1b2Fa2992247ffC68790B20a3Df1E7514B68"
+	res, err := client.SendTx(tokenup_sdk.TransactRequest{From: node.address(), To: to, Value: "0x1", Data: "0x"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	orig := res.Data.TxHash
+
+	// 节点建议值不变，新 gas price 至少提高10%
+	up, err := client.SpeedUp(orig, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if up.GasPrice != "0x28fa6ae00" || up.Nonce != 7 || up.Kind != tokenup_sdk.ReplaceSpeedUp {
+		t.Fatalf("speed up: %+v", up)
+	}
+	tx := last()
+	if tx.To != to || tx.Value != "0x1" || tx.Nonce != 7 {
+		t.Fatalf("speed up request: %+v", tx)
+	}
+
+	cancel, err := client.Cancel(up.NewHash)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tx = last()
+	if tx.To != node.address() || tx.Value != "0x0" || tx.Nonce != 7 || tx.GasLimit != "0x5208" {
+		t.Fatalf("cancel request: %+v", tx)
+	}
+
+	lineage := client.TxLineage(cancel.NewHash)
+	if len(lineage) != 2 || lineage[0].OldHash != orig || lineage[1].NewHash != cancel.NewHash {
+		t.Fatalf("lineage: %+v", lineage)
+	}
+	if by, ok := client.ReplacedBy(orig); !ok || by != up.NewHash {
+		t.Fatalf("ReplacedBy(%s) = %s, %v", orig, by, ok)
+	}
+}
@@ -0,0 +1,140 @@
+package tokenup_sdk
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+const (
+	defaultCallbackWindow = 5 * time.Minute
+	defaultNonceCacheSize = 10000
+)
+
+var (
+	ErrCallbackExpired  = errors.New("callback timestamp out of acceptance window")
+	ErrCallbackReplayed = errors.New("callback nonce already seen")
+	ErrCallbackNoNonce  = errors.New("callback nonce missing")
+	ErrNonceCacheFull   = errors.New("nonce cache full of unexpired nonces")
+)
+
+// CallbackConfig 回调防重放配置
+// CallbackWindow 为回调 timestamp 与本地时间允许的最大偏差，0 使用默认的5分钟，负数表示不校验
+// NonceCache 为已处理 nonce 的存储，为 nil 时使用进程内 LRUNonceCache(容量10000)，多实例部署时应替换为共享存储
+// 默认缓存只淘汰已过期的 nonce，窗口内回调超过容量时拒绝新回调(ErrNonceCacheFull)而不是放过可能的重放
+type CallbackConfig struct {
+	CallbackWindow time.Duration
+	NonceCache     NonceCache
+}
+
+// NonceCache 记录已出现过的 nonce
+// Seen 在 nonce 于 ttl 内出现过时返回 true，否则记录该 nonce 并返回 false，ttl 为 0 表示不过期
+// 无法记录 nonce 时(如容量已满)应返回错误，不能为了腾出空间删除未过期的 nonce，否则被删除的 nonce 可以重放
+type NonceCache interface {
+	Seen(nonce string, ttl time.Duration) (bool, error)
+}
+
+// NonceForgetter 可选接口，NonceCache 实现后，回调处理失败时会删除已记录的 nonce，节点用相同 nonce 重试时不会被当作重放
+type NonceForgetter interface {
+	Forget(nonce string) error
+}
+
+// LRUNonceCache 进程内 NonceCache，最多保存 size 个 nonce
+// 容量满时先清理已过期的 nonce，仍然没有空间时 Seen 返回 ErrNonceCacheFull，ttl 为 0 的 nonce 永不清理
+type LRUNonceCache struct {
+	mu    sync.Mutex
+	size  int
+	items map[string]time.Time
+}
+
+func NewLRUNonceCache(size int) *LRUNonceCache {
+	if size <= 0 {
+		size = defaultNonceCacheSize
+	}
+	return &LRUNonceCache{
+		size:  size,
+		items: make(map[string]time.Time),
+	}
+}
+
+func (c *LRUNonceCache) Seen(nonce string, ttl time.Duration) (bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	now := time.Now()
+	if expireAt, ok := c.items[nonce]; ok {
+		if expireAt.IsZero() || now.Before(expireAt) {
+			return true, nil
+		}
+		delete(c.items, nonce)
+	}
+	if len(c.items) >= c.size {
+		for n, expireAt := range c.items {
+			if !expireAt.IsZero() && !now.Before(expireAt) {
+				delete(c.items, n)
+			}
+		}
+		if len(c.items) >= c.size {
+			return false, ErrNonceCacheFull
+		}
+	}
+	var expireAt time.Time
+	if ttl > 0 {
+		expireAt = now.Add(ttl)
+	}
+	c.items[nonce] = expireAt
+	return false, nil
+}
+
+func (c *LRUNonceCache) Forget(nonce string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.items, nonce)
+	return nil
+}
+
+func (client *Client) nonceCache() NonceCache {
+	client.mu.Lock()
+	defer client.mu.Unlock()
+	if client.NonceCache == nil {
+		client.NonceCache = NewLRUNonceCache(defaultNonceCacheSize)
+	}
+	return client.NonceCache
+}
+
+func (client *Client) checkReplay(nonce string, timestamp int64) error {
+	if nonce == "" {
+		return ErrCallbackNoNonce
+	}
+	return checkFreshness(client.nonceCache(), client.CallbackWindow, nonce, timestamp, ErrCallbackExpired, ErrCallbackReplayed)
+}
+
+// forgetNonce 删除 checkReplay 记录的 nonce，NonceCache 未实现 NonceForgetter 时不做处理
+func (client *Client) forgetNonce(nonce string) error {
+	if f, ok := client.nonceCache().(NonceForgetter); ok {
+		return f.Forget(nonce)
+	}
+	return nil
+}
+
+// checkFreshness 校验 timestamp 在 window 内且 nonce 未出现过，window 为 0 使用默认值，负数不校验时间
+func checkFreshness(cache NonceCache, window time.Duration, nonce string, timestamp int64, errExpired, errReplayed error) error {
+	if window == 0 {
+		window = defaultCallbackWindow
+	}
+	var ttl time.Duration
+	if window > 0 {
+		d := time.Since(time.Unix(timestamp, 0))
+		if d > window || d < -window {
+			return errExpired
+		}
+		ttl = 2 * window
+	}
+	seen, err := cache.Seen(nonce, ttl)
+	if err != nil {
+		return err
+	}
+	if seen {
+		return errReplayed
+	}
+	return nil
+}
@@ -8,13 +8,11 @@ import (
 	"crypto/x509"
 	"encoding/base64"
 	"encoding/hex"
+	"encoding/pem"
 	"errors"
 	"fmt"
 	rand2 "math/rand"
-	"net/url"
 	"reflect"
-	"sort"
-	"strconv"
 	"strings"
 	"time"
 )
@@ -27,17 +25,35 @@ type Result struct {
 	Status Status      `json:"status"`
 	Data   interface{} `json:"data"`
 }
-type ProxySignSafe struct {
+
+// SignedEnvelope 签名服务请求的公共签名头，签名请求类型通过嵌入该结构体实现 Signable
+type SignedEnvelope struct {
 	AppId     string `json:"app_id" sign:"app_id"`
 	AppKey    string `json:"-" sign:"app_key"`
 	Nonce     string `json:"nonce" sign:"nonce"`
-	Address   string `json:"address" sign:"address"`
 	Timestamp int64  `json:"timestamp" sign:"timestamp"`
-	Data      string `json:"data" sign:"data"`
-	Extras    string `json:"extras" sign:"extras" gorm:"type:text"`
-	OrderID   string `json:"order_id" sign:"order_id"`
 	Signature string `json:"signature" gorm:"type:text"`
 }
+
+func (e *SignedEnvelope) Envelope() *SignedEnvelope { return e }
+
+// Signable 可以由 signerPost 填充签名头并签名的请求
+type Signable interface {
+	Envelope() *SignedEnvelope
+}
+
+var (
+	_ Signable = (*ProxySignSafe)(nil)
+	_ Signable = (*TraceSafe)(nil)
+)
+
+type ProxySignSafe struct {
+	SignedEnvelope
+	Address string `json:"address" sign:"address"`
+	Data    string `json:"data" sign:"data"`
+	Extras  string `json:"extras" sign:"extras" gorm:"type:text"`
+	OrderID string `json:"order_id" sign:"order_id"`
+}
 type SignSource struct {
 	Address string `json:"address"`
 	Data    string `json:"data" sign:"data"`
@@ -45,12 +61,8 @@ type SignSource struct {
 	OrderID string `json:"order_id" sign:"order_id"`
 }
 type TraceSafe struct {
-	AppId     string `json:"app_id" sign:"app_id"`
-	AppKey    string `sign:"app_key" json:"-"`
-	Nonce     string `json:"nonce" sign:"nonce"`
-	Timestamp int64  `json:"timestamp" sign:"timestamp"`
+	SignedEnvelope
 	RequestId string `json:"request_id" sign:"request_id"`
-	Signature string `json:"signature"`
 }
 type ReceivedConfirm struct {
 	Message   string `json:"message" sign:"message"`
@@ -137,169 +149,138 @@ func structCopy(srcPtr interface{}, desPtr interface{}) {
 }
 
 func RsaSignVerAndPublicHex(data []byte, signature, public string) error {
-	signatureDecode, err := hex.DecodeString(signature)
-	if err != nil {
-		return err
-	}
-	hashed := sha256.Sum256(data)
-	buff, _ := base64.StdEncoding.DecodeString(public)
-	// 解析公钥
-	pubInterface, err := x509.ParsePKIXPublicKey(buff)
+	pub, err := parseRsaPublicKey(public)
 	if err != nil {
 		return err
 	}
-	// 类型断言
-	pub := pubInterface.(*rsa.PublicKey)
-	//验证签名
-	return rsa.VerifyPKCS1v15(pub, crypto.SHA256, hashed[:], signatureDecode)
+	return rsaVerify(pub, data, signature)
 }
 func RsaSignAndPrivate(data []byte, privateKey string) (string, error) {
-	h := sha256.New()
-	h.Write(data)
-	hashed := h.Sum(nil)
-	buff, _ := base64.StdEncoding.DecodeString(privateKey)
-	//获取私钥
-	priv, err := x509.ParsePKCS1PrivateKey(buff)
+	priv, err := parseRsaPrivateKey(privateKey)
 	if err != nil {
 		return "", err
 	}
-	sign, err := rsa.SignPKCS1v15(rand.Reader, priv, crypto.SHA256, hashed)
-	return hex.EncodeToString(sign), err
-}
-
-type Dec []string
-
-func (p Dec) Len() int           { return len(p) }
-func (p Dec) Less(i, j int) bool { return p[i] < p[j] }
-func (p Dec) Swap(i, j int)      { p[i], p[j] = p[j], p[i] }
-func EncodeString(o interface{}) string {
-	dec := encodeField("", o)
-	sort.Stable(dec)
-	return strings.Join(dec, "&")
+	return rsaSign(priv, data)
 }
 
-func encodeField(prefix string, o interface{}) Dec {
-	t := reflect.TypeOf(o)
-	v := reflect.ValueOf(o)
-	valStr, err := getValueString(v)
-	var dec Dec
-	if err == nil {
-		values := url.Values{}
-		values.Set("url", valStr)
-		return append(dec, prefix+"="+strings.Split(values.Encode(), "=")[1])
-	}
-	if prefix != "" {
-		prefix += "."
+// RsaSignWithSigner 使用任意 crypto.Signer(HSM、PKCS#11、KMS代理等)签名，签名算法同 RsaSignAndPrivate
+func RsaSignWithSigner(data []byte, signer crypto.Signer) (string, error) {
+	if err := checkRsaSigner(signer); err != nil {
+		return "", err
 	}
+	return rsaSign(signer, data)
+}
 
-	if t.Kind() == reflect.Ptr {
-		t = t.Elem()
-		v = v.Elem()
+// parseRsaPublicKey 支持 PEM(PUBLIC KEY / RSA PUBLIC KEY / CERTIFICATE) 与 base64 编码的 PKIX、PKCS#1 DER
+func parseRsaPublicKey(public string) (*rsa.PublicKey, error) {
+	der, blockType, err := decodeKey(public)
+	if err != nil {
+		return nil, fmt.Errorf("parse public key: %v", err)
 	}
-
-	for i := 0; i < t.NumField(); i++ {
-		prefix2 := t.Field(i).Tag.Get("sign")
-		value := v.Field(i)
-		if prefix2 == "" {
-			continue
-		}
-		arrs := strings.Split(prefix2, ",")
-		if len(arrs) >= 1 {
-			prefix2 = arrs[0]
+	var pubInterface interface{}
+	switch blockType {
+	case "RSA PUBLIC KEY":
+		pubInterface, err = x509.ParsePKCS1PublicKey(der)
+	case "CERTIFICATE":
+		var cert *x509.Certificate
+		if cert, err = x509.ParseCertificate(der); err == nil {
+			pubInterface = cert.PublicKey
 		}
-		if isArray(value) {
-			for j := 0; j < value.Len(); j++ {
-				dec = append(dec, encodeField(prefix+prefix2+"[]", value.Index(j).Interface())...)
+	default:
+		// 解析公钥
+		if pubInterface, err = x509.ParsePKIXPublicKey(der); err != nil && blockType == "" {
+			if pub, err2 := x509.ParsePKCS1PublicKey(der); err2 == nil {
+				pubInterface, err = pub, nil
 			}
-		} else if value.Kind() == reflect.Map {
-			dataMap := value.Interface().(map[string]interface{})
-			for k, v := range dataMap {
-				dec = append(dec, encodeField(prefix+prefix2+"."+k, v)...)
-			}
-
-		} else {
-			dec = append(dec, encodeField(prefix+prefix2, value.Interface())...)
-		}
-
-	}
-	return dec
-}
-func getValueString(value reflect.Value) (string, error) {
-	if value.Type().String() == "string" {
-		return value.Interface().(string), nil
-	}
-	if value.Type().String() == "bool" {
-		val := value.Interface().(bool)
-		if val {
-			return "true", nil
-		} else {
-			return "false", nil
 		}
 	}
-	if value.Type().String() == "int8" {
-		val := value.Interface().(int8)
-		return strconv.FormatInt(int64(val), 10), nil
-	}
-	if value.Type().String() == "int16" {
-		val := value.Interface().(int16)
-		return strconv.FormatInt(int64(val), 10), nil
-	}
-	if value.Type().String() == "int32" {
-		val := value.Interface().(int32)
-		return strconv.FormatInt(int64(val), 10), nil
+	if err != nil {
+		return nil, fmt.Errorf("parse public key: %v", err)
 	}
-	if value.Type().String() == "int64" {
-		val := value.Interface().(int64)
-		return strconv.FormatInt(val, 10), nil
+	// 类型断言
+	pub, ok := pubInterface.(*rsa.PublicKey)
+	if !ok {
+		return nil, fmt.Errorf("parse public key: %T is not an RSA public key", pubInterface)
 	}
-	if value.Type().String() == "uint8" {
-		val := value.Interface().(uint8)
-		return strconv.FormatUint(uint64(val), 10), nil
+	return pub, nil
+}
+
+// parseRsaPrivateKey 支持 PEM(RSA PRIVATE KEY / PRIVATE KEY) 与 base64 编码的 PKCS#1、PKCS#8 DER
+func parseRsaPrivateKey(privateKey string) (*rsa.PrivateKey, error) {
+	der, blockType, err := decodeKey(privateKey)
+	if err != nil {
+		return nil, fmt.Errorf("parse private key: %v", err)
 	}
-	if value.Type().String() == "uint16" {
-		val := value.Interface().(uint16)
-		return strconv.FormatUint(uint64(val), 10), nil
+	//获取私钥
+	var privInterface interface{}
+	switch blockType {
+	case "RSA PRIVATE KEY":
+		privInterface, err = x509.ParsePKCS1PrivateKey(der)
+	case "PRIVATE KEY":
+		privInterface, err = x509.ParsePKCS8PrivateKey(der)
+	case "":
+		if privInterface, err = x509.ParsePKCS1PrivateKey(der); err != nil {
+			if priv, err2 := x509.ParsePKCS8PrivateKey(der); err2 == nil {
+				privInterface, err = priv, nil
+			}
+		}
+	default:
+		err = fmt.Errorf("unsupported PEM block type %q", blockType)
 	}
-	if value.Type().String() == "uint32" {
-		val := value.Interface().(uint32)
-		return strconv.FormatUint(uint64(val), 10), nil
+	if err != nil {
+		return nil, fmt.Errorf("parse private key: %v", err)
 	}
-	if value.Type().String() == "int" {
-		val := value.Interface().(int)
-		return strconv.FormatInt(int64(val), 10), nil
+	priv, ok := privInterface.(*rsa.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("parse private key: %T is not an RSA private key", privInterface)
 	}
-	if value.Type().String() == "uint" {
-		val := value.Interface().(uint)
-		return strconv.FormatUint(uint64(val), 10), nil
+	return priv, nil
+}
+
+// decodeKey 返回密钥的 DER 数据，PEM 格式时同时返回 block 类型
+func decodeKey(key string) ([]byte, string, error) {
+	key = strings.TrimSpace(key)
+	if key == "" {
+		return nil, "", errors.New("empty key")
 	}
-	if value.Type().String() == "uint64" {
-		val := value.Interface().(uint64)
-		return strconv.FormatUint(val, 10), nil
+	if strings.HasPrefix(key, "-----BEGIN") {
+		block, _ := pem.Decode([]byte(key))
+		if block == nil {
+			return nil, "", errors.New("invalid PEM data")
+		}
+		return block.Bytes, block.Type, nil
 	}
-	if value.Type().String() == "float32" {
-		val := value.Interface()
-		var timeFloat64 float64
-		_, _ = fmt.Sscanf(fmt.Sprint(val), "%e", &timeFloat64)
-		return strconv.FormatFloat(timeFloat64, 'E', -1, 32), nil
+	der, err := base64.StdEncoding.DecodeString(key)
+	if err != nil {
+		return nil, "", fmt.Errorf("invalid base64 key: %v", err)
 	}
-	if value.Type().String() == "float64" {
-		val := value.Interface()
-		var timeFloat64 float64
-		_, _ = fmt.Sscanf(fmt.Sprint(val), "%e", &timeFloat64)
-		return strconv.FormatFloat(timeFloat64, 'E', -1, 64), nil
+	return der, "", nil
+}
+
+func checkRsaSigner(signer crypto.Signer) error {
+	if signer == nil {
+		return errors.New("nil signer")
 	}
-	if value.Type().String() == "uint32" {
-		val := value.Interface().(uint32)
-		return strconv.FormatUint(uint64(val), 10), nil
+	if _, ok := signer.Public().(*rsa.PublicKey); !ok {
+		return fmt.Errorf("signer public key %T is not an RSA public key", signer.Public())
 	}
-
-	return "", errors.New("invalid field")
-
+	return nil
 }
 
-func isArray(value reflect.Value) bool {
-	if strings.HasPrefix(value.Type().String(), "[]") {
-		return true
+func rsaVerify(pub *rsa.PublicKey, data []byte, signature string) error {
+	signatureDecode, err := hex.DecodeString(signature)
+	if err != nil {
+		return err
+	}
+	hashed := sha256.Sum256(data)
+	//验证签名
+	return rsa.VerifyPKCS1v15(pub, crypto.SHA256, hashed[:], signatureDecode)
+}
+func rsaSign(signer crypto.Signer, data []byte) (string, error) {
+	hashed := sha256.Sum256(data)
+	sign, err := signer.Sign(rand.Reader, hashed[:], crypto.SHA256)
+	if err != nil {
+		return "", err
 	}
-	return false
+	return hex.EncodeToString(sign), nil
 }
@@ -0,0 +1,67 @@
+package tokenup_sdk_test
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"github.com/cblk/tokenup-sdk"
+	"testing"
+)
+
+func TestRsaKeyFormats(t *testing.T) {
+	priv, err := rsa.GenerateKey(rand.Reader, 1024)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pkcs8, _ := x509.MarshalPKCS8PrivateKey(priv)
+	pkix, _ := x509.MarshalPKIXPublicKey(&priv.PublicKey)
+	privKeys := map[string]string{
+		"pkcs1 base64": base64.StdEncoding.EncodeToString(x509.MarshalPKCS1PrivateKey(priv)),
+		"pkcs8 base64": base64.StdEncoding.EncodeToString(pkcs8),
+		"pkcs1 pem":    string(pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(priv)})),
+		"pkcs8 pem":    string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: pkcs8})),
+	}
+	pubKeys := map[string]string{
+		"pkix base64": base64.StdEncoding.EncodeToString(pkix),
+		"pkix pem":    string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: pkix})),
+		"pkcs1 pem":   string(pem.EncodeToMemory(&pem.Block{Type: "RSA PUBLIC KEY", Bytes: x509.MarshalPKCS1PublicKey(&priv.PublicKey)})),
+	}
+	data := []byte("app_id=app&nonce=1")
+	for privName, privKey := range privKeys {
+		sig, err := tokenup_sdk.RsaSignAndPrivate(data, privKey)
+		if err != nil {
+			t.Fatalf("%s: %v", privName, err)
+		}
+		for pubName, pubKey := range pubKeys {
+			if err := tokenup_sdk.RsaSignVerAndPublicHex(data, sig, pubKey); err != nil {
+				t.Errorf("%s/%s: %v", privName, pubName, err)
+			}
+		}
+	}
+
+	sig, err := tokenup_sdk.RsaSignWithSigner(data, priv)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := tokenup_sdk.RsaSignVerAndPublicHex(data, sig, pubKeys["pkix base64"]); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestRsaKeyErrors(t *testing.T) {
+	if _, err := tokenup_sdk.RsaSignAndPrivate(nil, "not base64!"); err == nil {
+		t.Error("invalid base64 private key accepted")
+	}
+	ec, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	ecPub, _ := x509.MarshalPKIXPublicKey(&ec.PublicKey)
+	if err := tokenup_sdk.RsaSignVerAndPublicHex(nil, "00", base64.StdEncoding.EncodeToString(ecPub)); err == nil {
+		t.Error("ECDSA public key accepted")
+	}
+	if _, err := tokenup_sdk.RsaSignWithSigner(nil, ec); err == nil {
+		t.Error("ECDSA signer accepted")
+	}
+}
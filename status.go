@@ -0,0 +1,115 @@
+package tokenup_sdk
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// TxStatus 交易状态
+type TxStatus int
+
+const (
+	TxStatusNone TxStatus = iota
+	TxStatusPending
+	TxStatusConfirmed
+	TxStatusFailed
+)
+
+var txStatusNames = []string{"none", "pending", "confirmed", "failed"}
+
+func (s TxStatus) String() string { return enumName(txStatusNames, int(s), "TxStatus") }
+
+// IsFinal 交易已进入终态(Confirmed/Failed)
+func (s TxStatus) IsFinal() bool { return s == TxStatusConfirmed || s == TxStatusFailed }
+
+// IsSuccess 交易已确认且执行成功
+func (s TxStatus) IsSuccess() bool { return s == TxStatusConfirmed }
+
+func (s TxStatus) MarshalJSON() ([]byte, error) { return []byte(strconv.Itoa(int(s))), nil }
+
+func (s *TxStatus) UnmarshalJSON(b []byte) error {
+	return unmarshalEnum(b, txStatusNames, "TxStatus", (*int)(s))
+}
+
+// NotifyStatus 节点回调通知状态
+type NotifyStatus int
+
+const (
+	NotifyStatusNone NotifyStatus = iota
+	NotifyStatusNotified
+)
+
+var notifyStatusNames = []string{"none", "notified"}
+
+func (s NotifyStatus) String() string { return enumName(notifyStatusNames, int(s), "NotifyStatus") }
+
+// IsNotified 节点已完成回调通知
+func (s NotifyStatus) IsNotified() bool { return s == NotifyStatusNotified }
+
+func (s NotifyStatus) MarshalJSON() ([]byte, error) { return []byte(strconv.Itoa(int(s))), nil }
+
+func (s *NotifyStatus) UnmarshalJSON(b []byte) error {
+	return unmarshalEnum(b, notifyStatusNames, "NotifyStatus", (*int)(s))
+}
+
+// TxType 交易类型
+type TxType int
+
+const (
+	TxTypeContractCreation TxType = iota
+	TxTypeContractCall
+	TxTypeTransfer
+)
+
+var txTypeNames = []string{"contract_creation", "contract_call", "transfer"}
+
+func (t TxType) String() string { return enumName(txTypeNames, int(t), "TxType") }
+
+func (t TxType) IsContractCreation() bool { return t == TxTypeContractCreation }
+
+func (t TxType) MarshalJSON() ([]byte, error) { return []byte(strconv.Itoa(int(t))), nil }
+
+func (t *TxType) UnmarshalJSON(b []byte) error {
+	return unmarshalEnum(b, txTypeNames, "TxType", (*int)(t))
+}
+
+func enumName(names []string, v int, typ string) string {
+	if v >= 0 && v < len(names) {
+		return names[v]
+	}
+	return fmt.Sprintf("%s(%d)", typ, v)
+}
+
+// unmarshalEnum 接受节点返回的数字，也接受 String() 对应的名称(不区分大小写)
+func unmarshalEnum(b []byte, names []string, typ string, v *int) error {
+	b = bytes.TrimSpace(b)
+	if bytes.Equal(b, []byte("null")) {
+		return nil
+	}
+	if len(b) > 0 && b[0] == '"' {
+		var s string
+		if err := json.Unmarshal(b, &s); err != nil {
+			return err
+		}
+		for i, name := range names {
+			if strings.EqualFold(s, name) {
+				*v = i
+				return nil
+			}
+		}
+		if n, err := strconv.Atoi(s); err == nil {
+			*v = n
+			return nil
+		}
+		return fmt.Errorf("invalid %s %q", typ, s)
+	}
+	n, err := strconv.Atoi(string(b))
+	if err != nil {
+		return fmt.Errorf("invalid %s %s", typ, b)
+	}
+	*v = n
+	return nil
+}
@@ -0,0 +1,36 @@
+package tokenup_sdk_test
+
+import (
+	"encoding/json"
+	"github.com/cblk/tokenup-sdk"
+	"testing"
+)
+
+func TestTxStatusJSON(t *testing.T) {
+	var res tokenup_sdk.DetailResponse
+	if err := json.Unmarshal([]byte(`{"data":{"status":2,"notify_status":"notified","type":"Transfer"}}`), &res); err != nil {
+		t.Fatal(err)
+	}
+	d := res.Data
+	if d.Status != tokenup_sdk.TxStatusConfirmed || d.NotifyStatus != tokenup_sdk.NotifyStatusNotified || d.Type != tokenup_sdk.TxTypeTransfer {
+		t.Fatalf("%+v", d)
+	}
+	if !d.Status.IsFinal() || !d.Status.IsSuccess() || !d.NotifyStatus.IsNotified() {
+		t.Fatal("predicates")
+	}
+	if tokenup_sdk.TxStatusFailed.IsSuccess() || tokenup_sdk.TxStatusPending.IsFinal() {
+		t.Fatal("predicates")
+	}
+	if s := tokenup_sdk.TxStatus(9).String(); s != "TxStatus(9)" {
+		t.Fatal(s)
+	}
+	out, _ := json.Marshal(struct {
+		S tokenup_sdk.TxStatus `json:"s"`
+	}{tokenup_sdk.TxStatusFailed})
+	if string(out) != `{"s":3}` {
+		t.Fatal(string(out))
+	}
+	if err := json.Unmarshal([]byte(`{"data":{"status":"bogus"}}`), &res); err == nil {
+		t.Fatal("invalid status accepted")
+	}
+}
@@ -1,10 +1,12 @@
 package tokenup_sdk
 
 import (
+	"fmt"
 	"github.com/ethereum/go-ethereum/accounts/abi"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rlp"
 	"math/big"
 	"strings"
 )
@@ -17,6 +19,7 @@ type EstimateRequest struct {
 	From        string `json:"from" validate:"eth_addr" description:"交易发送方地址"`
 	To          string `json:"to" validate:"omitempty,eth_addr" description:"交易目标地址"`
 	Data        string `json:"data" validate:"omitempty,is_hex" description:"交易数据(16进制字符串)"`
+	Value       string `json:"value" validate:"omitempty,is_hex_num" description:"要发送到目标地址的以太数量(16进制字符串)"`
 	FeeLimit    int64  `json:"fee_limit" validate:"lte=500000000" description:"交易费用上限，不大于500000000（Gwei）"`
 	GasPriceMin int64  `json:"gas_price_min" validate:"gte=1000000000" description:"gas price下限，不小于1000000000（Wei）"`
 	GasPriceMax int64  `json:"gas_price_max" validate:"lte=100000000000" description:"gas price上限，不大于100000000000（Wei）"`
@@ -42,17 +45,22 @@ type TransactRequest struct {
 	GasLimit  string `json:"gas_limit" validate:"is_hex_num" description:"交易的gas上限(16进制字符串)"`
 	Signature string `json:"signature" validate:"signature" description:"交易数据签名(16进制字符串)"`
 	NotifyUrl string `json:"notify_url" validate:"omitempty,url" description:"通知回调url"`
+	// GasStrategy 未指定 GasPrice 时使用，优先于 NodeConfig.GasStrategy
+	GasStrategy GasStrategy `json:"-"`
+	// MaxFee/MaxValue 本笔交易的费用与金额上限(Wei)，与 NodeConfig 上的上限同时生效
+	MaxFee   *big.Int `json:"-"`
+	MaxValue *big.Int `json:"-"`
 }
 
 type TransactResponse struct {
 	Response
 	Data struct {
-		GasPrice     string `json:"gas_price" description:"交易发送方愿意支付的gas价格(16进制字符串)"`
-		GasLimit     string `json:"gas_limit" description:"交易的gas上限(16进制字符串)"`
-		TxHash       string `json:"tx_hash" description:"交易哈希"`
-		Status       int    `json:"status" description:"交易状态：0=None 1=Pending 2=Confirmed 3=Failed"`
-		NotifyStatus int    `json:"notify_status" description:"通知状态：0=未通知 1=已通知"`
-		Type         int    `json:"type" description:"交易类型：0=创建合约 1=合约调用 2=转账"`
+		GasPrice     string       `json:"gas_price" description:"交易发送方愿意支付的gas价格(16进制字符串)"`
+		GasLimit     string       `json:"gas_limit" description:"交易的gas上限(16进制字符串)"`
+		TxHash       string       `json:"tx_hash" description:"交易哈希"`
+		Status       TxStatus     `json:"status" description:"交易状态：0=None 1=Pending 2=Confirmed 3=Failed"`
+		NotifyStatus NotifyStatus `json:"notify_status" description:"通知状态：0=未通知 1=已通知"`
+		Type         TxType       `json:"type" description:"交易类型：0=创建合约 1=合约调用 2=转账"`
 	} `json:"data" description:"发送交易结果"`
 }
 
@@ -63,16 +71,19 @@ type DetailRequest struct {
 type DetailResponse struct {
 	Response
 	Data struct {
-		From         string `json:"from" description:"交易发送方地址"`
-		To           string `json:"to" description:"交易目标地址"`
-		Nonce        uint64 `json:"nonce" description:"交易序列号"`
-		Data         string `json:"data"`
-		GasPrice     string `json:"gas_price" description:"交易发送方愿意支付的gas价格(16进制字符串)"`
-		GasLimit     string `json:"gas_limit" description:"交易的gas上限(16进制字符串)"`
-		TxHash       string `json:"tx_hash" description:"交易哈希"`
-		Status       int    `json:"status" description:"交易状态：0=None 1=Pending 2=Confirmed 3=Failed"`
-		NotifyStatus int    `json:"notify_status" description:"通知状态：0=未通知 1=已通知"`
-		Type         int    `json:"type" description:"交易类型：0=创建合约 1=合约调用 2=转账"`
+		From         string       `json:"from" description:"交易发送方地址"`
+		To           string       `json:"to" description:"交易目标地址"`
+		Nonce        uint64       `json:"nonce" description:"交易序列号"`
+		Data         string       `json:"data"`
+		Value        string       `json:"value" description:"发送到目标地址的以太数量(16进制字符串)"`
+		GasPrice     string       `json:"gas_price" description:"交易发送方愿意支付的gas价格(16进制字符串)"`
+		GasLimit     string       `json:"gas_limit" description:"交易的gas上限(16进制字符串)"`
+		TxHash       string       `json:"tx_hash" description:"交易哈希"`
+		Status       TxStatus     `json:"status" description:"交易状态：0=None 1=Pending 2=Confirmed 3=Failed"`
+		NotifyStatus NotifyStatus `json:"notify_status" description:"通知状态：0=未通知 1=已通知"`
+		Type         TxType       `json:"type" description:"交易类型：0=创建合约 1=合约调用 2=转账"`
+		BlockNumber  uint64       `json:"block_number" description:"交易所在区块高度，未上链时为0"`
+		Receipt      *Receipt     `json:"receipt" description:"交易收据，未上链时为空"`
 	} `json:"data" description:"交易详情"`
 }
 
@@ -92,7 +103,7 @@ type QueryRequest struct {
 	BlockHash string     `json:"block_hash" validate:"omitempty,is_hex" description:"区块哈希"`
 	FromBlock int64      `json:"from_block" validate:"gte=0" description:"开始区块高度"`
 	ToBlock   int64      `json:"to_block" validate:"gte=0" description:"结束区块高度"`
-	Addresses []string   `json:"addresses" description:"合约地址列表"`
+	Addresses []string   `json:"addresses" validate:"dive,eth_addr" description:"合约地址列表"`
 	Topics    [][]string `json:"topics" description:"事件的topic列表"`
 }
 
@@ -111,32 +122,68 @@ type Event struct {
 }
 
 func (tx TransactRequest) decode(chainId int64) (string, error) {
-	amount, _ := hexutil.DecodeBig(tx.Value)
-	gasLimit, _ := hexutil.DecodeUint64(tx.GasLimit)
-	gasPrice, _ := hexutil.DecodeBig(tx.GasPrice)
-	data, _ := hexutil.Decode(tx.Data)
-	var tran *types.Transaction
+	t, err := tx.transaction()
+	if err != nil {
+		return "", err
+	}
+	h := types.NewEIP155Signer(big.NewInt(chainId)).Hash(t)
+	return hexutil.Encode(h[:]), nil
+}
+
+// unsignedRLP 返回 EIP-155 签名原文 [nonce, gasPrice, gas, to, value, data, chainId, 0, 0] 的 RLP 编码，
+// 其 keccak256 即 decode 返回的哈希
+func (tx TransactRequest) unsignedRLP(chainId int64) ([]byte, error) {
+	t, err := tx.transaction()
+	if err != nil {
+		return nil, err
+	}
+	return rlp.EncodeToBytes([]interface{}{t.Nonce(), t.GasPrice(), t.Gas(), t.To(), t.Value(), t.Data(), big.NewInt(chainId), uint(0), uint(0)})
+}
+
+// transaction 按请求构造未签名交易，Value 与 Data 为空时视为0与无数据
+func (tx TransactRequest) transaction() (*types.Transaction, error) {
+	amount := new(big.Int)
+	if tx.Value != "" {
+		v, err := hexutil.DecodeBig(tx.Value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid value %q: %v", tx.Value, err)
+		}
+		amount = v
+	}
+	gasLimit, err := hexutil.DecodeUint64(tx.GasLimit)
+	if err != nil {
+		return nil, fmt.Errorf("invalid gas_limit %q: %v", tx.GasLimit, err)
+	}
+	gasPrice, err := hexutil.DecodeBig(tx.GasPrice)
+	if err != nil {
+		return nil, fmt.Errorf("invalid gas_price %q: %v", tx.GasPrice, err)
+	}
+	var data []byte
+	if tx.Data != "" {
+		if data, err = hexutil.Decode(tx.Data); err != nil {
+			return nil, fmt.Errorf("invalid data %q: %v", tx.Data, err)
+		}
+	}
 	if tx.To == "" {
-		tran = types.NewContractCreation(
+		return types.NewContractCreation(
 			tx.Nonce,
 			amount,
 			gasLimit,
 			gasPrice,
 			data,
-		)
-	} else {
-		tran = types.NewTransaction(
-			tx.Nonce,
-			common.HexToAddress(tx.To),
-			amount,
-			gasLimit,
-			gasPrice,
-			data,
-		)
+		), nil
 	}
-	mySigner := types.NewEIP155Signer(big.NewInt(chainId))
-	h := mySigner.Hash(tran)
-	return hexutil.Encode(h[:]), nil
+	if !common.IsHexAddress(tx.To) {
+		return nil, fmt.Errorf("invalid to %q", tx.To)
+	}
+	return types.NewTransaction(
+		tx.Nonce,
+		common.HexToAddress(tx.To),
+		amount,
+		gasLimit,
+		gasPrice,
+		data,
+	), nil
 }
 
 func GetData(abiStr, name string, args ...interface{}) (string, error) {
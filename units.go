@@ -0,0 +1,148 @@
+package tokenup_sdk
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"math/big"
+	"strings"
+)
+
+// Unit 以太金额单位，值为相对 Wei 的小数位数
+type Unit int
+
+const (
+	Wei   Unit = 0
+	Gwei  Unit = 9
+	Ether Unit = 18
+)
+
+var unitNames = map[string]Unit{"wei": Wei, "gwei": Gwei, "ether": Ether, "eth": Ether}
+
+func (u Unit) String() string {
+	switch u {
+	case Wei:
+		return "wei"
+	case Gwei:
+		return "gwei"
+	case Ether:
+		return "ether"
+	}
+	return fmt.Sprintf("Unit(%d)", int(u))
+}
+
+// ParseUnits 将十进制金额按 unit 转换为 Wei，如 ParseUnits("1.5", Ether)，小数位超过 unit 精度时返回错误
+func ParseUnits(amount string, unit Unit) (*big.Int, error) {
+	s := strings.TrimSpace(amount)
+	if s == "" {
+		return nil, errors.New("empty amount")
+	}
+	if strings.HasPrefix(s, "-") {
+		return nil, fmt.Errorf("negative amount %q", amount)
+	}
+	s = strings.TrimPrefix(s, "+")
+	whole, frac := s, ""
+	if i := strings.IndexByte(s, '.'); i >= 0 {
+		whole, frac = s[:i], s[i+1:]
+	}
+	frac = strings.TrimRight(frac, "0")
+	if len(frac) > int(unit) {
+		return nil, fmt.Errorf("amount %q has more than %d decimals for %v", amount, int(unit), unit)
+	}
+	if whole == "" {
+		whole = "0"
+	}
+	digits := whole + frac + strings.Repeat("0", int(unit)-len(frac))
+	if strings.ContainsAny(digits, "+-") {
+		return nil, fmt.Errorf("invalid amount %q", amount)
+	}
+	wei, ok := new(big.Int).SetString(digits, 10)
+	if !ok {
+		return nil, fmt.Errorf("invalid amount %q", amount)
+	}
+	return wei, nil
+}
+
+// ParseAmount 解析带单位的金额，如 "1.5 ether"、"20 gwei"、"100 wei"，返回 Wei
+// 不带单位的十进制整数视为 Wei，0x 开头的16进制字符串视为 Wei
+func ParseAmount(amount string) (*big.Int, error) {
+	s := strings.TrimSpace(amount)
+	if strings.HasPrefix(s, "0x") || strings.HasPrefix(s, "0X") {
+		wei, err := hexutil.DecodeBig(strings.ToLower(s))
+		if err != nil {
+			return nil, fmt.Errorf("invalid amount %q: %v", amount, err)
+		}
+		return wei, nil
+	}
+	unit := Wei
+	lower := strings.ToLower(s)
+	for name, u := range unitNames {
+		if strings.HasSuffix(lower, name) {
+			num := strings.TrimSpace(s[:len(s)-len(name)])
+			// gwei 与 wei 有相同后缀，需要保证前面不是字母
+			if num != "" && !isLetter(num[len(num)-1]) {
+				s, unit = num, u
+				break
+			}
+		}
+	}
+	return ParseUnits(s, unit)
+}
+
+func isLetter(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z'
+}
+
+// FormatUnits 将 Wei 按 unit 格式化为十进制字符串，去掉小数末尾的0，如 FormatUnits(1500000000000000000, Ether) 为 "1.5"
+func FormatUnits(wei *big.Int, unit Unit) string {
+	if wei == nil {
+		return "0"
+	}
+	sign := ""
+	digits := wei.String()
+	if wei.Sign() < 0 {
+		sign, digits = "-", digits[1:]
+	}
+	if n := int(unit); n > 0 {
+		if len(digits) <= n {
+			digits = strings.Repeat("0", n-len(digits)+1) + digits
+		}
+		whole, frac := digits[:len(digits)-n], strings.TrimRight(digits[len(digits)-n:], "0")
+		digits = whole
+		if frac != "" {
+			digits += "." + frac
+		}
+	}
+	return sign + digits
+}
+
+// FormatEther 将 Wei 格式化为 Ether，如 "1.5"
+func FormatEther(wei *big.Int) string { return FormatUnits(wei, Ether) }
+
+// FormatGwei 将 Wei 格式化为 Gwei，如 "20"
+func FormatGwei(wei *big.Int) string { return FormatUnits(wei, Gwei) }
+
+// Transfer 与 SendTx 相同，从 from 向 to 转账 wei，ctx 结束时中止正在进行的估算、签名与广播请求
+func (client *Client) Transfer(ctx context.Context, from, to string, wei *big.Int) (TransactResponse, error) {
+	if wei == nil || wei.Sign() < 0 {
+		return TransactResponse{}, fmt.Errorf("invalid transfer amount %v", wei)
+	}
+	if to == "" {
+		return TransactResponse{}, errors.New("transfer needs a recipient")
+	}
+	return client.sendTxContext(ctx, TransactRequest{
+		From:  from,
+		To:    to,
+		Value: hexutil.EncodeBig(wei),
+	})
+}
+
+// TransferAmount 与 Transfer 相同，amount 为 ParseAmount 支持的字符串，如 "0.01 ether"
+func (client *Client) TransferAmount(ctx context.Context, from, to, amount string) (TransactResponse, error) {
+	wei, err := ParseAmount(amount)
+	if err != nil {
+		return TransactResponse{}, err
+	}
+	return client.Transfer(ctx, from, to, wei)
+}
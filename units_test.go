@@ -0,0 +1,99 @@
+package tokenup_sdk_test
+
+import (
+	"context"
+	"errors"
+	"github.com/cblk/tokenup-sdk"
+	"math/big"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestParseAmount(t *testing.T) {
+	cases := []struct {
+		in   string
+		want string
+	}{
+		{"1.5 ether", "1500000000000000000"},
+		{"1.5ETH", "1500000000000000000"},
+		{"20 gwei", "20000000000"},
+		{"0.000000001 gwei", "1"},
+		{"0.0000000001 gwei", ""},
+		{"100 wei", "100"},
+		{"100", "100"},
+		{"0x3e8", "1000"},
+		{"1.5", ""},
+		{"-1 ether", ""},
+		{"1e18", ""},
+		{"1.5 btc", ""},
+	}
+	for _, c := range cases {
+		got, err := tokenup_sdk.ParseAmount(c.in)
+		if c.want == "" {
+			if err == nil {
+				t.Errorf("%q: parsed as %v, want error", c.in, got)
+			}
+			continue
+		}
+		if err != nil || got.String() != c.want {
+			t.Errorf("%q: got %v %v, want %s", c.in, got, err, c.want)
+		}
+	}
+}
+
+func TestFormatUnits(t *testing.T) {
+	wei, _ := new(big.Int).SetString("1500000000000000000", 10)
+	if s := tokenup_sdk.FormatEther(wei); s != "1.5" {
+		t.Errorf("ether %s", s)
+	}
+	if s := tokenup_sdk.FormatGwei(big.NewInt(20000000000)); s != "20" {
+		t.Errorf("gwei %s", s)
+	}
+	if s := tokenup_sdk.FormatEther(big.NewInt(1)); s != "0.000000000000000001" {
+		t.Errorf("1 wei %s", s)
+	}
+	if s := tokenup_sdk.FormatUnits(big.NewInt(-1500000000), tokenup_sdk.Gwei); s != "-1.5" {
+		t.Errorf("negative %s", s)
+	}
+}
+
+func TestTransfer(t *testing.T) {
+	node, client := newFakeNode(t)
+	node.acceptTx()
+	to := "0xdF0F1b2Fa2992247ffC68790B20a3Df1E7514B68"
+	if _, err := client.TransferAmount(context.Background(), node.address(), to, "0.01 ether"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.Transfer(context.Background(), node.address(), to, big.NewInt(1000)); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.TransferAmount(context.Background(), node.address(), to, "1 wie"); err == nil {
+		t.Fatal("invalid amount accepted")
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := client.TransferAmount(ctx, node.address(), to, "1 wei"); !errors.Is(err, context.Canceled) {
+		t.Fatalf("cancelled transfer: %v", err)
+	}
+	sent := node.sent()
+	if len(sent) != 2 || sent[0].Value != "0x2386f26fc10000" || sent[1].Value != "0x3e8" {
+		t.Fatalf("sent %+v", sent)
+	}
+
+	// 节点一直不响应时 ctx 超时中止正在进行的广播
+	release := make(chan struct{})
+	defer close(release)
+	node.handle("/v1/tx/transact", func(string, []byte) (int, interface{}) {
+		<-release
+		return http.StatusServiceUnavailable, "released"
+	})
+	ctx, cancel = context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if _, err := client.Transfer(ctx, node.address(), to, big.NewInt(1)); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("timed out transfer: %v", err)
+	}
+	if n := node.count("/v1/tx/transact"); n != 3 {
+		t.Fatalf("timed out before broadcasting: %d transact requests", n)
+	}
+}
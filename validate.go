@@ -0,0 +1,181 @@
+package tokenup_sdk
+
+import (
+	"fmt"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// FieldError 请求字段未通过 validate 标签校验
+type FieldError struct {
+	Field string      // 结构体字段名，如 TransactRequest.Value
+	Tag   string      // 未通过的规则，如 is_hex_num
+	Value interface{} // 字段的值
+	Msg   string
+}
+
+func (e *FieldError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Field, e.Msg)
+}
+
+// ValidationErrors 一次校验中所有未通过的字段
+type ValidationErrors []*FieldError
+
+func (errs ValidationErrors) Error() string {
+	msgs := make([]string, len(errs))
+	for i, e := range errs {
+		msgs[i] = e.Error()
+	}
+	return "invalid request: " + strings.Join(msgs, "; ")
+}
+
+// Validate 按字段的 validate 标签校验请求，失败时返回 ValidationErrors
+// 支持 omitempty、dive、eth_addr(大小写混合时校验 EIP-55 checksum)、is_hex、is_hex_num、signature(非空，编码由签名服务决定)、url、lte=N、gte=N
+func Validate(req interface{}) error {
+	return validate(req)
+}
+
+// validate 校验 req，optional 中的字段为空时跳过(由 SDK 补全的字段)
+func validate(req interface{}, optional ...string) error {
+	v := reflect.Indirect(reflect.ValueOf(req))
+	if v.Kind() != reflect.Struct {
+		return fmt.Errorf("validate: %T is not a struct", req)
+	}
+	t := v.Type()
+	var errs ValidationErrors
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag := f.Tag.Get("validate")
+		if tag == "" || f.PkgPath != "" {
+			continue
+		}
+		rules := strings.Split(tag, ",")
+		if isOptional(f.Name, optional) {
+			rules = append([]string{"omitempty"}, rules...)
+		}
+		errs = append(errs, validateValue(t.Name()+"."+f.Name, v.Field(i), rules)...)
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+func isOptional(name string, optional []string) bool {
+	for _, o := range optional {
+		if o == name {
+			return true
+		}
+	}
+	return false
+}
+
+func validateValue(field string, v reflect.Value, rules []string) ValidationErrors {
+	var errs ValidationErrors
+	for i, rule := range rules {
+		switch rule {
+		case "omitempty":
+			if v.IsZero() {
+				return nil
+			}
+			continue
+		case "dive":
+			for j := 0; j < v.Len(); j++ {
+				errs = append(errs, validateValue(fmt.Sprintf("%s[%d]", field, j), v.Index(j), rules[i+1:])...)
+			}
+			return errs
+		}
+		if msg := checkRule(rule, v); msg != "" {
+			name := rule
+			if k := strings.IndexByte(rule, '='); k >= 0 {
+				name = rule[:k]
+			}
+			errs = append(errs, &FieldError{Field: field, Tag: name, Value: v.Interface(), Msg: msg})
+		}
+	}
+	return errs
+}
+
+// checkRule 返回未通过规则的原因，通过时返回空字符串
+func checkRule(rule string, v reflect.Value) string {
+	if k := strings.IndexByte(rule, '='); k >= 0 {
+		limit, err := strconv.ParseInt(rule[k+1:], 10, 64)
+		if err != nil {
+			return fmt.Sprintf("invalid rule %q", rule)
+		}
+		n, ok := intValue(v)
+		if !ok {
+			return fmt.Sprintf("rule %q needs an integer", rule)
+		}
+		switch rule[:k] {
+		case "lte":
+			if n > limit {
+				return fmt.Sprintf("%d must not be greater than %d", n, limit)
+			}
+		case "gte":
+			if n < limit {
+				return fmt.Sprintf("%d must not be less than %d", n, limit)
+			}
+		default:
+			return fmt.Sprintf("unknown rule %q", rule)
+		}
+		return ""
+	}
+	if v.Kind() != reflect.String {
+		return fmt.Sprintf("rule %q needs a string", rule)
+	}
+	s := v.String()
+	switch rule {
+	case "eth_addr":
+		return checkAddress(s)
+	case "is_hex":
+		if _, err := hexutil.Decode(s); err != nil {
+			return fmt.Sprintf("%q is not 0x-prefixed hex: %v", s, err)
+		}
+	case "is_hex_num":
+		if _, err := hexutil.DecodeBig(s); err != nil {
+			return fmt.Sprintf("%q is not a 0x-prefixed hex number: %v", s, err)
+		}
+	case "signature":
+		// 签名编码由签名服务决定，原样交给节点，这里只要求非空；PrepareTx 组装 RawTx 时另行要求65字节 [R || S || V]
+		if strings.TrimSpace(s) == "" {
+			return "signature is empty"
+		}
+	case "url":
+		if u, err := url.ParseRequestURI(s); err != nil || u.Scheme == "" || u.Host == "" {
+			return fmt.Sprintf("%q is not an absolute url", s)
+		}
+	default:
+		return fmt.Sprintf("unknown rule %q", rule)
+	}
+	return ""
+}
+
+// checkAddress 全小写或全大写地址不含 checksum，大小写混合时必须符合 EIP-55
+func checkAddress(s string) string {
+	if !common.IsHexAddress(s) || !strings.HasPrefix(s, "0x") {
+		return fmt.Sprintf("%q is not a 0x-prefixed 20 byte address", s)
+	}
+	body := s[2:]
+	if body == strings.ToLower(body) || body == strings.ToUpper(body) {
+		return ""
+	}
+	if want := common.HexToAddress(s).Hex(); s != want {
+		return fmt.Sprintf("%q has an invalid EIP-55 checksum, want %s", s, want)
+	}
+	return ""
+}
+
+func intValue(v reflect.Value) (int64, bool) {
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return v.Int(), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return int64(v.Uint()), true
+	}
+	return 0, false
+}
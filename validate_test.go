@@ -0,0 +1,65 @@
+package tokenup_sdk_test
+
+import (
+	"github.com/cblk/tokenup-sdk"
+	"strings"
+	"testing"
+)
+
+func TestValidate(t *testing.T) {
+	valid := tokenup_sdk.TransactRequest{
+		From:      "0xdF0F1b2Fa2992247ffC68790B20a3Df1E7514B68",
+		To:        "0xdf0f1b2fa2992247ffc68790b20a3df1e7514b68",
+		Value:     "0x1",
+		GasPrice:  "0x2540be400",
+		GasLimit:  "0x5208",
+		Signature: "0x" + strings.Repeat("11", 65),
+	}
+	if err := tokenup_sdk.Validate(valid); err != nil {
+		t.Fatal(err)
+	}
+	// 签名服务的返回值原样交给节点，不限定编码
+	raw := valid
+	raw.Signature = strings.Repeat("AB", 65)
+	if err := tokenup_sdk.Validate(raw); err != nil {
+		t.Fatal(err)
+	}
+	raw.Signature = ""
+	if err := tokenup_sdk.Validate(raw); err == nil {
+		t.Fatal("empty signature accepted")
+	}
+
+	bad := valid
+	bad.From = "0xdf0F1b2Fa2992247ffC68790B20a3Df1E7514B68"
+	bad.Value = "0xzz"
+	bad.NotifyUrl = "callback"
+	err := tokenup_sdk.Validate(bad)
+	errs, ok := err.(tokenup_sdk.ValidationErrors)
+	if !ok || len(errs) != 3 {
+		t.Fatalf("errors %v", err)
+	}
+	for i, want := range []string{"TransactRequest.From eth_addr", "TransactRequest.Value is_hex_num", "TransactRequest.NotifyUrl url"} {
+		if got := errs[i].Field + " " + errs[i].Tag; got != want {
+			t.Errorf("error %d = %s, want %s", i, got, want)
+		}
+	}
+
+	if err := tokenup_sdk.Validate(tokenup_sdk.EstimateRequest{From: valid.From, GasPriceMin: 1, GasPriceMax: 1}); err == nil {
+		t.Fatal("gas_price_min below 1 Gwei accepted")
+	}
+	query := tokenup_sdk.QueryRequest{Addresses: []string{valid.From, "0x01"}}
+	if errs, ok := tokenup_sdk.Validate(query).(tokenup_sdk.ValidationErrors); !ok || len(errs) != 1 || errs[0].Field != "QueryRequest.Addresses[1]" {
+		t.Fatalf("query errors %v", errs)
+	}
+}
+
+func TestSendTx_ValidatesBeforeNetwork(t *testing.T) {
+	node, client := newFakeNode(t)
+	_, err := client.SendTx(tokenup_sdk.TransactRequest{From: node.address(), To: "0xdF0F1b2Fa2992247ffC68790B20a3Df1E7514B68", Value: "1000"})
+	if _, ok := err.(tokenup_sdk.ValidationErrors); !ok {
+		t.Fatalf("err %v", err)
+	}
+	if node.count("/v1/tx/estimate") != 0 || len(node.signed()) != 0 {
+		t.Fatal("invalid request reached the node")
+	}
+}
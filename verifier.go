@@ -0,0 +1,162 @@
+package tokenup_sdk
+
+import (
+	"bytes"
+	"context"
+	"crypto/rsa"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"sync"
+	"time"
+)
+
+const maxVerifyBodySize = 1 << 20
+
+var (
+	ErrUnknownApp       = errors.New("unknown app_id")
+	ErrMissingSignature = errors.New("missing signature")
+	ErrRequestExpired   = errors.New("request timestamp out of acceptance window")
+	ErrRequestReplayed  = errors.New("request nonce already seen")
+)
+
+// App 调用方应用，PublicKey 为应用签名私钥对应的公钥，格式同 Authorize.CallBackPartyPublicKey
+type App struct {
+	AppId     string
+	AppKey    string
+	PublicKey string
+}
+
+// AppLookup 按 app_id 查找应用，应用不存在时返回 nil, nil
+type AppLookup func(appId string) (*App, error)
+
+// Verifier 服务端校验与签名服务相同格式的签名请求(app_id/nonce/timestamp/signature)
+// app_key 不在请求体中传输，由 Lookup 返回的 App 补充，签名原文规则见 CanonicalVersion
+// NewRequest 返回请求体对应的类型时，请求体解码到该类型后按 sign 标签选择字段，与客户端签名完全相同；
+// 为 nil 时请求体按 map 解码，除 signature 外的所有字段都参与签名，数字按 JSON 原文处理：
+// 客户端结构体中的字段都必须带 sign 标签且与 json 名相同，并且不能有浮点数字段(float 2 签名为 2E+00，JSON 中为 2)，否则需要设置 NewRequest
+// NonceCache 与 CallbackConfig.NonceCache 相同，为 nil 时使用进程内 LRUNonceCache，容量满时拒绝请求
+type Verifier struct {
+	Lookup     AppLookup
+	MaxSkew    time.Duration
+	NonceCache NonceCache
+	NewRequest func() Signable
+
+	once sync.Once
+	keys sync.Map
+}
+
+type appContextKey struct{}
+
+func NewVerifier(lookup AppLookup) *Verifier {
+	return &Verifier{Lookup: lookup}
+}
+
+// Verify 校验原始请求体，成功时返回发起请求的应用
+func (v *Verifier) Verify(body []byte) (*App, error) {
+	fields := map[string]interface{}{}
+	dec := json.NewDecoder(bytes.NewReader(body))
+	dec.UseNumber()
+	if err := dec.Decode(&fields); err != nil {
+		return nil, err
+	}
+	appId, _ := fields["app_id"].(string)
+	nonce, _ := fields["nonce"].(string)
+	signature, _ := fields["signature"].(string)
+	if signature == "" {
+		return nil, ErrMissingSignature
+	}
+	if nonce == "" {
+		return nil, errors.New("missing nonce")
+	}
+	ts, ok := fields["timestamp"].(json.Number)
+	if !ok {
+		return nil, errors.New("missing timestamp")
+	}
+	timestamp, err := ts.Int64()
+	if err != nil {
+		return nil, fmt.Errorf("invalid timestamp: %v", err)
+	}
+	app, err := v.Lookup(appId)
+	if err != nil {
+		return nil, err
+	}
+	if app == nil {
+		return nil, ErrUnknownApp
+	}
+	pub, err := v.publicKey(app.PublicKey)
+	if err != nil {
+		return nil, err
+	}
+	plain, err := v.canonical(body, fields, app)
+	if err != nil {
+		return nil, err
+	}
+	if err := rsaVerify(pub, []byte(plain), signature); err != nil {
+		return nil, err
+	}
+	if err := checkFreshness(v.nonceCache(), v.MaxSkew, appId+":"+nonce, timestamp, ErrRequestExpired, ErrRequestReplayed); err != nil {
+		return nil, err
+	}
+	return app, nil
+}
+
+func (v *Verifier) canonical(body []byte, fields map[string]interface{}, app *App) (string, error) {
+	if v.NewRequest == nil {
+		delete(fields, "signature")
+		fields["app_key"] = app.AppKey
+		return CanonicalString(fields)
+	}
+	req := v.NewRequest()
+	if err := json.Unmarshal(body, req); err != nil {
+		return "", err
+	}
+	req.Envelope().AppKey = app.AppKey
+	return CanonicalString(req)
+}
+
+// Middleware 校验请求签名，失败时返回401，成功时可以在后续 handler 中通过 AppFromContext 获取应用
+func (v *Verifier) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := ioutil.ReadAll(http.MaxBytesReader(w, r.Body, maxVerifyBodySize))
+		if err != nil {
+			writeNotifyResponse(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		app, err := v.Verify(body)
+		if err != nil {
+			writeNotifyResponse(w, http.StatusUnauthorized, err.Error())
+			return
+		}
+		r.Body = ioutil.NopCloser(bytes.NewReader(body))
+		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), appContextKey{}, app)))
+	})
+}
+
+func AppFromContext(ctx context.Context) (*App, bool) {
+	app, ok := ctx.Value(appContextKey{}).(*App)
+	return app, ok
+}
+
+func (v *Verifier) nonceCache() NonceCache {
+	v.once.Do(func() {
+		if v.NonceCache == nil {
+			v.NonceCache = NewLRUNonceCache(defaultNonceCacheSize)
+		}
+	})
+	return v.NonceCache
+}
+
+func (v *Verifier) publicKey(key string) (*rsa.PublicKey, error) {
+	if pub, ok := v.keys.Load(key); ok {
+		return pub.(*rsa.PublicKey), nil
+	}
+	pub, err := parseRsaPublicKey(key)
+	if err != nil {
+		return nil, err
+	}
+	v.keys.Store(key, pub)
+	return pub, nil
+}
@@ -0,0 +1,125 @@
+package tokenup_sdk_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"github.com/cblk/tokenup-sdk"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func signedBody(t *testing.T, priv string, nonce string, ts int64) []byte {
+	ps := tokenup_sdk.ProxySignSafe{
+		SignedEnvelope: tokenup_sdk.SignedEnvelope{
+			AppId:     "app",
+			AppKey:    "key",
+			Nonce:     nonce,
+			Timestamp: ts,
+		},
+		Address: "0xabc",
+		Data:    "0x01",
+		Extras:  "a b&c",
+		OrderID: "o1",
+	}
+	plain, err := tokenup_sdk.CanonicalString(&ps)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ps.Signature, err = tokenup_sdk.RsaSignAndPrivate([]byte(plain), priv); err != nil {
+		t.Fatal(err)
+	}
+	body, _ := json.Marshal(ps)
+	return body
+}
+
+func TestVerifier_Middleware(t *testing.T) {
+	priv, pub := newTestKeys(t)
+	v := tokenup_sdk.NewVerifier(func(appId string) (*tokenup_sdk.App, error) {
+		if appId != "app" {
+			return nil, nil
+		}
+		return &tokenup_sdk.App{AppId: "app", AppKey: "key", PublicKey: pub}, nil
+	})
+	h := v.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		app, ok := tokenup_sdk.AppFromContext(r.Context())
+		body, _ := ioutil.ReadAll(r.Body)
+		if !ok || app.AppId != "app" || len(body) == 0 {
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+	do := func(body []byte) int {
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/", bytes.NewReader(body)))
+		return w.Code
+	}
+
+	body := signedBody(t, priv, "n1", time.Now().Unix())
+	if code := do(body); code != http.StatusOK {
+		t.Fatalf("valid request: code=%d", code)
+	}
+	if code := do(body); code != http.StatusUnauthorized {
+		t.Fatalf("replayed request: code=%d", code)
+	}
+	if _, err := v.Verify(signedBody(t, priv, "n2", time.Now().Add(-time.Hour).Unix())); err != tokenup_sdk.ErrRequestExpired {
+		t.Fatalf("want ErrRequestExpired, got %v", err)
+	}
+	tampered := bytes.Replace(signedBody(t, priv, "n3", time.Now().Unix()), []byte("0xabc"), []byte("0xabd"), 1)
+	if _, err := v.Verify(tampered); err == nil {
+		t.Fatal("tampered request accepted")
+	}
+}
+
+type quoteRequest struct {
+	tokenup_sdk.SignedEnvelope
+	Amount float64 `json:"amount" sign:"amount"`
+	Memo   string  `json:"memo"`
+}
+
+func TestVerifier_RoundTrip(t *testing.T) {
+	priv, pub := newTestKeys(t)
+	lookup := func(appId string) (*tokenup_sdk.App, error) {
+		return &tokenup_sdk.App{AppId: appId, AppKey: "key", PublicKey: pub}, nil
+	}
+	sign := func(nonce, memo string) []byte {
+		req := quoteRequest{
+			SignedEnvelope: tokenup_sdk.SignedEnvelope{AppId: "app", AppKey: "key", Nonce: nonce, Timestamp: time.Now().Unix()},
+			Amount:         2,
+			Memo:           memo,
+		}
+		plain, err := tokenup_sdk.CanonicalString(&req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if req.Signature, err = tokenup_sdk.RsaSignAndPrivate([]byte(plain), priv); err != nil {
+			t.Fatal(err)
+		}
+		body, _ := json.Marshal(req)
+		return body
+	}
+
+	typed := tokenup_sdk.NewVerifier(lookup)
+	typed.NewRequest = func() tokenup_sdk.Signable { return &quoteRequest{} }
+	if _, err := typed.Verify(sign("n1", "not signed")); err != nil {
+		t.Fatalf("typed verifier: %v", err)
+	}
+
+	// 没有 NewRequest 时所有字段都参与签名
+	untyped := tokenup_sdk.NewVerifier(lookup)
+	if _, err := untyped.Verify(sign("n2", "")); err == nil {
+		t.Fatal("untagged memo field should be rejected by map verifier")
+	}
+	// 去掉 memo 后 float 字段仍然不一致，只能通过 NewRequest 校验
+	var fields map[string]interface{}
+	_ = json.Unmarshal(sign("n3", ""), &fields)
+	delete(fields, "memo")
+	body, _ := json.Marshal(fields)
+	if _, err := untyped.Verify(body); err == nil {
+		t.Fatal("float field should be rejected by map verifier")
+	}
+	if _, err := typed.Verify(body); err != nil {
+		t.Fatalf("typed verifier: %v", err)
+	}
+}
@@ -0,0 +1,161 @@
+package tokenup_sdk
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+const defaultWaitPollInterval = 2 * time.Second
+
+type TxOutcome int
+
+const (
+	TxOutcomeSuccess TxOutcome = iota + 1
+	TxOutcomeReverted
+	// TxOutcomeDropped 交易被丢弃或被相同 nonce 的交易替换
+	TxOutcomeDropped
+	TxOutcomeTimeout
+)
+
+func (o TxOutcome) String() string {
+	switch o {
+	case TxOutcomeSuccess:
+		return "success"
+	case TxOutcomeReverted:
+		return "reverted"
+	case TxOutcomeDropped:
+		return "dropped"
+	case TxOutcomeTimeout:
+		return "timeout"
+	}
+	return fmt.Sprintf("TxOutcome(%d)", int(o))
+}
+
+// WaitOptions WaitForTx 的选项
+// Confirmations 为交易确认后额外等待的区块数，节点网关不提供当前区块高度，需要同时设置 HeadBlock 返回链上最新高度；
+// Notifications 为 TxNotifyHandler 转发的节点回调，收到对应交易的回调时立即查询；
+// DropTimeout 大于0时，交易在该时长内一直查询不到则视为已被丢弃
+type WaitOptions struct {
+	PollInterval  time.Duration
+	Confirmations uint64
+	HeadBlock     func() (uint64, error)
+	Notifications <-chan TxNotification
+	DropTimeout   time.Duration
+}
+
+// TxResult 交易的最终结果，ReplacedBy 为替换了该交易的交易哈希(通过 SpeedUp/Cancel 发送时)
+type TxResult struct {
+	Outcome       TxOutcome
+	TxHash        string
+	ReplacedBy    string
+	BlockNumber   uint64
+	Confirmations uint64
+	Detail        DetailResponse
+}
+
+// WaitForTx 等待交易进入终态(Confirmed/Failed)以及所需的确认数，ctx 结束时返回 TxOutcomeTimeout 与 ctx.Err()
+// 查询交易时的网络错误、节点5xx与429会在下一次轮询时重试，参数错误与节点其它4xx立即返回
+func (client *Client) WaitForTx(ctx context.Context, txHash string, opts WaitOptions) (TxResult, error) {
+	interval := opts.PollInterval
+	if interval <= 0 {
+		interval = defaultWaitPollInterval
+	}
+	result := TxResult{TxHash: txHash}
+	if opts.Confirmations > 0 && opts.HeadBlock == nil {
+		return result, errors.New("WaitOptions.Confirmations requires HeadBlock")
+	}
+	lastSeen := time.Now()
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		done, err := client.pollTx(&result, opts, &lastSeen)
+		if err != nil || done {
+			return result, err
+		}
+		if err := waitNextPoll(ctx, ticker.C, &opts.Notifications, txHash); err != nil {
+			result.Outcome = TxOutcomeTimeout
+			return result, err
+		}
+	}
+}
+
+// waitNextPoll 等待下一次轮询或收到 txHash 的节点回调
+func waitNextPoll(ctx context.Context, tick <-chan time.Time, notifications *<-chan TxNotification, txHash string) error {
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-tick:
+			return nil
+		case n, ok := <-*notifications:
+			if !ok {
+				*notifications = nil
+			} else if n.TxHash == txHash {
+				return nil
+			}
+		}
+	}
+}
+
+func (client *Client) pollTx(result *TxResult, opts WaitOptions, lastSeen *time.Time) (bool, error) {
+	detail, err := client.TxDetail(result.TxHash)
+	if err != nil {
+		if ne, ok := err.(*NodeError); !ok || ne.Code != http.StatusNotFound {
+			if retryableTxError(err) {
+				return false, nil
+			}
+			return false, err
+		}
+	}
+	result.Detail = detail
+	switch detail.Data.Status {
+	case TxStatusFailed:
+		result.Outcome = TxOutcomeReverted
+		result.BlockNumber = detail.Data.BlockNumber
+		return true, nil
+	case TxStatusConfirmed:
+		result.BlockNumber = detail.Data.BlockNumber
+		if opts.Confirmations > 0 {
+			head, err := opts.HeadBlock()
+			if err != nil {
+				return false, err
+			}
+			if head > result.BlockNumber {
+				result.Confirmations = head - result.BlockNumber
+			}
+			if result.Confirmations < opts.Confirmations {
+				return false, nil
+			}
+		}
+		result.Outcome = TxOutcomeSuccess
+		return true, nil
+	}
+	if newHash, ok := client.ReplacedBy(result.TxHash); ok {
+		result.Outcome = TxOutcomeDropped
+		result.ReplacedBy = newHash
+		return true, nil
+	}
+	if detail.Data.Status == TxStatusPending {
+		*lastSeen = time.Now()
+		return false, nil
+	}
+	if opts.DropTimeout > 0 && time.Since(*lastSeen) > opts.DropTimeout {
+		result.Outcome = TxOutcomeDropped
+		return true, nil
+	}
+	return false, nil
+}
+
+// retryableTxError 查询交易的错误是否可以在下一次轮询时重试
+func retryableTxError(err error) bool {
+	switch e := err.(type) {
+	case ValidationErrors:
+		return false
+	case *NodeError:
+		return e.Code >= http.StatusInternalServerError || e.Code == http.StatusTooManyRequests
+	}
+	return true
+}
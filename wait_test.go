@@ -0,0 +1,150 @@
+package tokenup_sdk_test
+
+import (
+	"context"
+	"github.com/cblk/tokenup-sdk"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// txStatus 测试中的交易状态与链上最新高度，serve 作为 fakeNode 上 tx/{hash} 的 handler
+type txStatus struct {
+	mu      sync.Mutex
+	head    uint64
+	details map[string]map[string]interface{}
+}
+
+func newTxStatus(node *fakeNode) *txStatus {
+	s := &txStatus{details: map[string]map[string]interface{}{}}
+	node.handle("/v1/tx/", s.serve)
+	return s
+}
+
+func (s *txStatus) update(hash string, fields map[string]interface{}) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.details[hash] == nil {
+		s.details[hash] = map[string]interface{}{"tx_hash": hash}
+	}
+	for k, v := range fields {
+		s.details[hash][k] = v
+	}
+}
+
+func (s *txStatus) setHead(head uint64) {
+	s.mu.Lock()
+	s.head = head
+	s.mu.Unlock()
+}
+
+func (s *txStatus) headBlock() (uint64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.head, nil
+}
+
+func (s *txStatus) serve(path string, _ []byte) (int, interface{}) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	detail, ok := s.details[strings.TrimPrefix(path, "/v1/tx/")]
+	if !ok {
+		return http.StatusNotFound, "not found"
+	}
+	// fakeNode 在锁外编码返回值，返回副本避免与 update 并发读写
+	copied := make(map[string]interface{}, len(detail))
+	for k, v := range detail {
+		copied[k] = v
+	}
+	return http.StatusOK, copied
+}
+
+func TestWaitForTx(t *testing.T) {
+	node, client := newFakeNode(t)
+	b := newTxStatus(node)
+	if _, err := client.WaitForTx(context.Background(), "0x01", tokenup_sdk.WaitOptions{Confirmations: 3}); err == nil {
+		t.Fatal("confirmations without HeadBlock accepted")
+	}
+	opts := tokenup_sdk.WaitOptions{PollInterval: 10 * time.Millisecond, Confirmations: 3, HeadBlock: b.headBlock}
+
+	b.update("0x01", map[string]interface{}{"status": tokenup_sdk.TxStatusConfirmed, "block_number": 100})
+	b.setHead(101)
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		b.setHead(103)
+	}()
+	res, err := client.WaitForTx(context.Background(), "0x01", opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Outcome != tokenup_sdk.TxOutcomeSuccess || res.BlockNumber != 100 || res.Confirmations != 3 {
+		t.Fatalf("confirmed: %+v", res)
+	}
+
+	b.update("0x02", map[string]interface{}{"status": tokenup_sdk.TxStatusFailed, "block_number": 100})
+	if res, err := client.WaitForTx(context.Background(), "0x02", opts); err != nil || res.Outcome != tokenup_sdk.TxOutcomeReverted {
+		t.Fatalf("failed: %+v %v", res, err)
+	}
+
+	b.update("0x03", map[string]interface{}{"status": tokenup_sdk.TxStatusPending})
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if res, err := client.WaitForTx(ctx, "0x03", opts); err != context.DeadlineExceeded || res.Outcome != tokenup_sdk.TxOutcomeTimeout {
+		t.Fatalf("timeout: %+v %v", res, err)
+	}
+
+	opts.DropTimeout = 30 * time.Millisecond
+	if res, err := client.WaitForTx(context.Background(), "0x04", opts); err != nil || res.Outcome != tokenup_sdk.TxOutcomeDropped {
+		t.Fatalf("dropped: %+v %v", res, err)
+	}
+}
+
+func TestWaitForTx_Notification(t *testing.T) {
+	node, client := newFakeNode(t)
+	b := newTxStatus(node)
+	b.update("0x01", map[string]interface{}{"status": tokenup_sdk.TxStatusPending})
+	notifications := make(chan tokenup_sdk.TxNotification, 1)
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		b.update("0x01", map[string]interface{}{"status": tokenup_sdk.TxStatusConfirmed, "block_number": 100})
+		notifications <- tokenup_sdk.TxNotification{TxHash: "0x01", Status: tokenup_sdk.TxStatusConfirmed}
+	}()
+	start := time.Now()
+	res, err := client.WaitForTx(context.Background(), "0x01", tokenup_sdk.WaitOptions{PollInterval: time.Hour, Notifications: notifications})
+	if err != nil || res.Outcome != tokenup_sdk.TxOutcomeSuccess {
+		t.Fatalf("%+v %v", res, err)
+	}
+	if time.Since(start) > time.Second {
+		t.Fatal("notification did not trigger a poll")
+	}
+}
+
+func TestWaitForTx_RetriesTransientErrors(t *testing.T) {
+	node, client := newFakeNode(t)
+	b := newTxStatus(node)
+	b.update("0x01", map[string]interface{}{"status": tokenup_sdk.TxStatusConfirmed, "block_number": 100})
+	var mu sync.Mutex
+	failures := 2
+	node.handle("/v1/tx/", func(path string, body []byte) (int, interface{}) {
+		mu.Lock()
+		defer mu.Unlock()
+		if strings.HasSuffix(path, "/0x02") {
+			return http.StatusBadRequest, "invalid tx hash"
+		}
+		if failures > 0 {
+			failures--
+			return http.StatusServiceUnavailable, "upstream unavailable"
+		}
+		return b.serve(path, body)
+	})
+	opts := tokenup_sdk.WaitOptions{PollInterval: 10 * time.Millisecond}
+	res, err := client.WaitForTx(context.Background(), "0x01", opts)
+	if err != nil || res.Outcome != tokenup_sdk.TxOutcomeSuccess || node.count("/v1/tx/0x01") != 3 {
+		t.Fatalf("%+v %v after %d polls", res, err, node.count("/v1/tx/0x01"))
+	}
+	if _, err := client.WaitForTx(context.Background(), "0x02", opts); err == nil {
+		t.Fatal("non-retryable node error ignored")
+	}
+}